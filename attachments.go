@@ -72,6 +72,33 @@ type MessageAttachment struct {
 func (n *Node) UploadImage(fileName string, r io.Reader, size int64) (*MessageAttachment, error) {
 	// Upload to app.valour.gg/image/upload
 	// Base64 decode response for url
+	upload, err := n.uploadImage("upload/image", fileName, r, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageAttachment{
+		Location: upload.Location,
+		FileName: fileName,
+		Width:    upload.Width,
+		Height:   upload.Height,
+		Type:     AttachmentTypeImage,
+	}, nil
+}
+
+// imageUpload is the result of an image upload
+type imageUpload struct {
+	// Body is the raw response, usually the location of the uploaded image
+	Body     []byte
+	Location string
+	Format   string
+	Width    int
+	Height   int
+}
+
+// uploadImage checks that r is a supported image and uploads it as a multipart form to uri
+func (n *Node) uploadImage(uri, fileName string, r io.Reader, size int64) (*imageUpload, error) {
 	s := multipart.New()
 
 	var header bytes.Buffer
@@ -85,6 +112,7 @@ func (n *Node) UploadImage(fileName string, r io.Reader, size int64) (*MessageAt
 	}
 
 	log.WithFields(log.Fields{
+		"uri":      uri,
 		"fileName": fileName,
 		"size":     size,
 		"format":   format,
@@ -92,13 +120,6 @@ func (n *Node) UploadImage(fileName string, r io.Reader, size int64) (*MessageAt
 		"height":   cfg.Height,
 	}).Debug("Image ready for upload")
 
-	attachment := &MessageAttachment{
-		FileName: fileName,
-		Width:    cfg.Width,
-		Height:   cfg.Height,
-		Type:     AttachmentTypeImage,
-	}
-
 	// Re-combine bytes we read and the rest of the data
 	mr := io.MultiReader(&header, r)
 
@@ -112,7 +133,7 @@ func (n *Node) UploadImage(fileName string, r io.Reader, size int64) (*MessageAt
 		return nil, err
 	}
 
-	res, err := n.request(http.MethodPost, "upload/image", s)
+	res, err := n.request(http.MethodPost, uri, s)
 
 	if err != nil {
 		return nil, err
@@ -128,9 +149,13 @@ func (n *Node) UploadImage(fileName string, r io.Reader, size int64) (*MessageAt
 		return nil, fmt.Errorf("upload failed with status code %d: %s", res.StatusCode, string(b))
 	}
 
-	attachment.Location = string(b)
-
-	return attachment, nil
+	return &imageUpload{
+		Body:     b,
+		Location: string(b),
+		Format:   format,
+		Width:    cfg.Width,
+		Height:   cfg.Height,
+	}, nil
 }
 
 // UploadFile uploads a file to Valour
@@ -1 +1,153 @@
 package valour
+
+import (
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxBanPageSize is the largest page of bans the API will return
+const maxBanPageSize = 100
+
+type Bans interface {
+	Ban(planetID PlanetID, id BanID) (*Ban, error)
+	PlanetBansPage(planetID PlanetID, skip, take int) (*PagedResponse[Ban], error)
+	PlanetBans(planetID PlanetID) ([]Ban, error)
+	PlanetBansIter(planetID PlanetID) iter.Seq2[Ban, error]
+	BanUser(planetID PlanetID, userID UserID, data BanData) (*Ban, error)
+	BanMember(memberID MemberID, data BanData) (*Ban, error)
+	Unban(planetID PlanetID, id BanID) error
+}
+
+// Ban is a user's ban from a planet
+type Ban struct {
+	ID          BanID      `json:"id"`
+	IssuerID    UserID     `json:"issuerId"`
+	TargetID    UserID     `json:"targetId"`
+	PlanetID    PlanetID   `json:"planetId"`
+	Reason      string     `json:"reason"`
+	TimeCreated time.Time  `json:"timeCreated"`
+	TimeExpires *time.Time `json:"timeExpires"`
+	Permanent   bool       `json:"permanent"`
+}
+
+// Expired checks whether a temporary ban has ended
+func (b Ban) Expired() bool {
+	return !b.Permanent && b.TimeExpires != nil && time.Now().After(*b.TimeExpires)
+}
+
+// BanData contains the options for a new ban
+type BanData struct {
+	Reason string
+
+	// Expires is when the ban ends, the ban is permanent if nil
+	Expires *time.Time
+}
+
+// Ban retrieves a single ban
+func (n *Node) Ban(planetID PlanetID, id BanID) (*Ban, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ban Ban
+
+	if err := node.requestJSON(http.MethodGet, id.Route(), nil, &ban); err != nil {
+		return nil, err
+	}
+
+	return &ban, nil
+}
+
+// PlanetBansPage retrieves a single page of a planet's bans
+func (n *Node) PlanetBansPage(planetID PlanetID, skip, take int) (*PagedResponse[Ban], error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := make(url.Values)
+	q.Set("skip", strconv.Itoa(skip))
+	q.Set("take", strconv.Itoa(clampPageSize(take, maxBanPageSize)))
+
+	var page PagedResponse[Ban]
+
+	if err := node.requestJSON(http.MethodGet, planetID.Route("bans")+"?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// PlanetBans retrieves every ban of a planet, requesting as many pages as needed
+func (n *Node) PlanetBans(planetID PlanetID) ([]Ban, error) {
+	return collect(n.PlanetBansIter(planetID))
+}
+
+// PlanetBansIter iterates over every ban of a planet, requesting pages as they're needed.
+// Iteration stops after the first error.
+func (n *Node) PlanetBansIter(planetID PlanetID) iter.Seq2[Ban, error] {
+	return pages(maxBanPageSize, func(skip, take int) (*PagedResponse[Ban], error) {
+		return n.PlanetBansPage(planetID, skip, take)
+	})
+}
+
+// BanUser bans a user from a planet, removing their member if they have one
+func (n *Node) BanUser(planetID PlanetID, userID UserID, data BanData) (*Ban, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := n.Me()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ban := Ban{
+		IssuerID:    me.ID,
+		TargetID:    userID,
+		PlanetID:    planetID,
+		Reason:      data.Reason,
+		TimeCreated: time.Now().UTC(),
+		TimeExpires: data.Expires,
+		Permanent:   data.Expires == nil,
+	}
+
+	var newBan Ban
+
+	if err := node.requestJSON(http.MethodPost, apiBanBase, ban, &newBan); err != nil {
+		return nil, err
+	}
+
+	return &newBan, nil
+}
+
+// BanMember bans a member's user from the member's planet
+func (n *Node) BanMember(memberID MemberID, data BanData) (*Ban, error) {
+	member, err := n.Member(memberID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return n.BanUser(member.PlanetID, member.UserID, data)
+}
+
+// Unban removes a ban, allowing the user to rejoin the planet
+func (n *Node) Unban(planetID PlanetID, id BanID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, id.Route(), nil)
+}
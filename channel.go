@@ -1,7 +1,10 @@
 package valour
 
 import (
+	"cmp"
+	"fmt"
 	"net/http"
+	"slices"
 	"time"
 )
 
@@ -19,6 +22,21 @@ const (
 	GroupVoice
 )
 
+// IsPlanet checks whether the channel type belongs to a planet
+func (t ChannelType) IsPlanet() bool {
+	return t <= PlanetVoice
+}
+
+// IsDirect checks whether the channel type is a direct channel between two users
+func (t ChannelType) IsDirect() bool {
+	return t == DirectChat || t == DirectVoice
+}
+
+// IsGroup checks whether the channel type is a group channel outside of a planet
+func (t ChannelType) IsGroup() bool {
+	return t == GroupChat || t == GroupVoice
+}
+
 type Channel struct {
 	ID             ChannelID       `json:"id"`
 	PlanetID       PlanetID        `json:"planetId"`
@@ -43,12 +61,47 @@ type ChannelPosition struct {
 type Channels interface {
 	Channel(planetID PlanetID, channelID ChannelID) (*Channel, error)
 	Channels(id PlanetID) ([]Channel, error)
+	CreateChannel(planetID PlanetID, data CreateChannelData) (*Channel, error)
+	EditChannel(planetID PlanetID, channelID ChannelID, data EditChannelData) (*Channel, error)
+	MoveChannel(planetID PlanetID, channelID ChannelID, parentID ChannelID, localPosition int) (*Channel, error)
+	ReorderChannels(planetID PlanetID, parentID ChannelID, order []ChannelID) error
+	DeleteChannel(planetID PlanetID, channelID ChannelID) error
+	DirectChannel(userID UserID) (*Channel, error)
+	DirectChannels() ([]Channel, error)
+	SendDirectMessage(userID UserID, content string) (*Message, error)
 }
 
+// CreateChannelData contains the fields for a new planet channel
+type CreateChannelData struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	ChannelType ChannelType `json:"channelType"`
+
+	// ParentID is the category to create the channel in, or NullChannelID for the top level
+	ParentID      ChannelID `json:"parentId,omitempty"`
+	NSFW          bool      `json:"nsfw"`
+	InheritsPerms bool      `json:"inheritsPerms"`
+}
+
+// EditChannelData is a partial update to a channel, only fields that are set are changed
+type EditChannelData struct {
+	Name          *string
+	Description   *string
+	NSFW          *bool
+	InheritsPerms *bool
+}
+
+// Channel retrieves a channel. Direct and group channels use NullPlanetID.
 func (n *Node) Channel(planetID PlanetID, channelID ChannelID) (*Channel, error) {
 	var channel Channel
 
-	if err := n.requestJSON(http.MethodGet, planetID.Route("channels", channelID.String()), nil, &channel); err != nil {
+	node, err := n.nodeForChannel(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := node.requestJSON(http.MethodGet, channelRoute(planetID, channelID), nil, &channel); err != nil {
 		return nil, err
 	}
 
@@ -71,3 +124,206 @@ func (n *Node) Channels(id PlanetID) ([]Channel, error) {
 
 	return channels, nil
 }
+
+// CreateChannel creates a chat, category or voice channel in a planet
+func (n *Node) CreateChannel(planetID PlanetID, data CreateChannelData) (*Channel, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := struct {
+		CreateChannelData
+		PlanetID PlanetID `json:"planetId"`
+	}{
+		CreateChannelData: data,
+		PlanetID:          planetID,
+	}
+
+	var channel Channel
+
+	if err := node.requestJSON(http.MethodPost, planetID.Route("channels"), fields, &channel); err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// EditChannel updates a channel's name, description, NSFW flag or permission inheritance
+func (n *Node) EditChannel(planetID PlanetID, channelID ChannelID, data EditChannelData) (*Channel, error) {
+	channel, err := n.Channel(planetID, channelID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	setIfNotNil(&channel.Name, data.Name)
+	setIfNotNil(&channel.Description, data.Description)
+	setIfNotNil(&channel.NSFW, data.NSFW)
+	setIfNotNil(&channel.InheritsPerms, data.InheritsPerms)
+
+	return n.updateChannel(*channel)
+}
+
+// MoveChannel moves a channel into a category, or the top level with NullChannelID,
+// at a position relative to the other channels there. The channels after it are shifted down.
+func (n *Node) MoveChannel(planetID PlanetID, channelID ChannelID, parentID ChannelID, localPosition int) (*Channel, error) {
+	channels, err := n.Channels(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(channels, func(c Channel) bool { return c.ID == channelID }) {
+		return nil, fmt.Errorf("channel %s not found in planet %s", channelID, planetID)
+	}
+
+	siblings := slices.DeleteFunc(channels, func(c Channel) bool {
+		return c.ParentID != parentID || c.ID == channelID
+	})
+
+	slices.SortFunc(siblings, func(a, b Channel) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.LocalPosition, b.Position.LocalPosition),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	order := make([]ChannelID, 0, len(siblings)+1)
+
+	for _, sibling := range siblings {
+		order = append(order, sibling.ID)
+	}
+
+	localPosition = max(0, min(localPosition, len(order)))
+
+	if err := n.ReorderChannels(planetID, parentID, slices.Insert(order, localPosition, channelID)); err != nil {
+		return nil, err
+	}
+
+	return n.Channel(planetID, channelID)
+}
+
+// ReorderChannels sets the order of the channels in a category, or the top level with NullChannelID.
+// The order must contain every channel that should be there, channels from elsewhere are moved into it.
+// The server assigns the positions, so no two channels share one.
+func (n *Node) ReorderChannels(planetID PlanetID, parentID ChannelID, order []ChannelID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	fields := struct {
+		CategoryID ChannelID   `json:"categoryId,omitempty"`
+		Order      []ChannelID `json:"order"`
+	}{
+		CategoryID: parentID,
+		Order:      order,
+	}
+
+	return node.requestNoContent(http.MethodPost, planetID.Route("channels", "order"), fields)
+}
+
+// DeleteChannel deletes a channel from a planet
+func (n *Node) DeleteChannel(planetID PlanetID, channelID ChannelID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, planetID.Route("channels", channelID.String()), nil)
+}
+
+func (n *Node) updateChannel(channel Channel) (*Channel, error) {
+	node, err := n.NodeForPlanet(channel.PlanetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Channel
+
+	if err := node.requestJSON(http.MethodPut, channel.PlanetID.Route("channels", channel.ID.String()), channel, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DirectChannel gets the direct channel with a user, opening one if it doesn't exist yet.
+// If the primary node is connected and the channel isn't joined yet, it's joined for realtime events.
+// This request always goes to the primary node
+func (n *Node) DirectChannel(userID UserID) (*Channel, error) {
+	if !n.IsPrimary() {
+		return n.Primary.DirectChannel(userID)
+	}
+
+	var channel Channel
+
+	if err := n.requestJSON(http.MethodPost, apiChannelBase+"/direct/"+userID.String()+"?create=true", nil, &channel); err != nil {
+		return nil, err
+	}
+
+	if n.rtc != nil && !n.rtc.Joined(channel.ID) {
+		if err := n.rtc.JoinChannel(channel.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &channel, nil
+}
+
+// DirectChannels retrieves the direct and group channels of the account
+// This request always goes to the primary node
+func (n *Node) DirectChannels() ([]Channel, error) {
+	if !n.IsPrimary() {
+		return n.Primary.DirectChannels()
+	}
+
+	var channels []Channel
+
+	if err := n.requestJSON(http.MethodGet, apiUserBase+"/me/channels", nil, &channels); err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(channels, func(c Channel) bool {
+		return c.ChannelType.IsPlanet()
+	}), nil
+}
+
+// SendDirectMessage sends a simple text message to a user, opening a direct channel if needed
+func (n *Node) SendDirectMessage(userID UserID, content string) (*Message, error) {
+	channel, err := n.DirectChannel(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return n.SendMessage(NullPlanetID, channel.ID, content)
+}
+
+// nodeForChannel returns the node for a planet's channels, or the primary node for
+// direct and group channels, which don't belong to a planet
+func (n *Node) nodeForChannel(planetID PlanetID) (*Node, error) {
+	if planetID.IsValid() {
+		return n.NodeForPlanet(planetID)
+	}
+
+	if !n.IsPrimary() {
+		return n.Primary, nil
+	}
+
+	return n, nil
+}
+
+// channelRoute returns the route of a planet channel, or a direct channel if planetID is NullPlanetID
+func channelRoute(planetID PlanetID, channelID ChannelID, path ...string) string {
+	if planetID.IsValid() {
+		return planetID.Route(append([]string{"channels", channelID.String()}, path...)...)
+	}
+
+	return channelID.Route(path...)
+}
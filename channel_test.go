@@ -0,0 +1,76 @@
+package valour
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestMoveChannel(t *testing.T) {
+	const planetID = PlanetID(1)
+
+	channels := []Channel{
+		{ID: 10, PlanetID: planetID, ChannelType: PlanetCategory},
+		{ID: 11, PlanetID: planetID, ParentID: 10, Position: ChannelPosition{LocalPosition: 0}},
+		{ID: 12, PlanetID: planetID, ParentID: 10, Position: ChannelPosition{LocalPosition: 1}},
+		{ID: 13, PlanetID: planetID, ParentID: 10, Position: ChannelPosition{LocalPosition: 2}},
+		{ID: 20, PlanetID: planetID, Position: ChannelPosition{LocalPosition: 1}},
+	}
+
+	var order struct {
+		CategoryID ChannelID   `json:"categoryId"`
+		Order      []ChannelID `json:"order"`
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/planets/1/channels", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, channels)
+	})
+	mux.HandleFunc("POST /api/planets/1/channels/order", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("GET /api/planets/1/channels/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := ParseSnowflake[ChannelID](r.PathValue("id"))
+		writeJSON(w, Channel{ID: id, PlanetID: planetID, ParentID: order.CategoryID})
+	})
+	mux.HandleFunc("PUT /api/planets/1/channels/{id}", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("MoveChannel should not PUT the whole channel")
+	})
+
+	node := newTestNode(t, mux)
+
+	moved, err := node.MoveChannel(planetID, 20, 10, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if order.CategoryID != 10 {
+		t.Fatalf("category = %s, want 10", order.CategoryID)
+	}
+
+	if want := []ChannelID{11, 20, 12, 13}; !slices.Equal(order.Order, want) {
+		t.Fatalf("order = %v, want %v", order.Order, want)
+	}
+
+	if moved.ParentID != 10 {
+		t.Fatalf("moved parent = %s, want 10", moved.ParentID)
+	}
+
+	// Positions past the end are clamped
+	if _, err := node.MoveChannel(planetID, 11, 10, 99); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []ChannelID{12, 13, 11}; !slices.Equal(order.Order, want) {
+		t.Fatalf("order = %v, want %v", order.Order, want)
+	}
+
+	if _, err := node.MoveChannel(planetID, 99, 10, 0); err == nil {
+		t.Fatal("expected an error for an unknown channel")
+	}
+}
@@ -0,0 +1,240 @@
+package valour
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the most characters Valour accepts in a message's content
+const MaxMessageLength = 2048
+
+// SplitContent splits content into chunks of at most limit bytes, preferring to split between lines,
+// then between words. Counting bytes never exceeds the limit however the characters are counted.
+// Code blocks split across chunks are closed and re-opened with the same language, unless the limit
+// is too small to fit the fences.
+// A limit of 0 uses MaxMessageLength, and limits below utf8.UTFMax are raised so any character fits.
+func SplitContent(content string, limit int) []string {
+	if limit <= 0 {
+		limit = MaxMessageLength
+	}
+
+	limit = max(limit, utf8.UTFMax)
+
+	if len(content) <= limit {
+		return []string{content}
+	}
+
+	s := &splitter{limit: limit}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		s.addLine(line)
+	}
+
+	s.flush()
+
+	return s.chunks
+}
+
+// splitter builds chunks line by line, tracking whether a code block is open
+type splitter struct {
+	limit  int
+	chunks []string
+	cur    strings.Builder
+
+	// reopened is the length of the code block re-opened at the start of the current chunk
+	reopened int
+
+	// fence is the opening line of the open code block, such as "```go", or empty outside of one
+	fence string
+}
+
+func (s *splitter) addLine(line string) {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case s.fence == "" && isOpeningFence(trimmed):
+		// An opening fence needs room to close the block it starts, and for some of its contents
+		need := len(line) + closeLength(trimmed) + utf8.UTFMax
+
+		if need > s.space() && s.hasContent() {
+			s.flush()
+		}
+
+		if need <= s.space() {
+			s.write(line)
+			s.fence = trimmed
+			return
+		}
+
+		// The fences can't fit in a chunk, so the block is split as plain text
+	case s.fence != "" && isClosingFence(trimmed, s.fence):
+		// The room kept for closing the block is available to the closing fence
+		if len(line) <= s.space()+s.reserve() {
+			s.write(line)
+			s.fence = ""
+			return
+		}
+
+		// Finishing the chunk closes the block, so the fence itself isn't needed
+		s.flush()
+		s.fence = ""
+		s.cur.Reset()
+		s.reopened = 0
+
+		return
+	}
+
+	s.addText(line)
+}
+
+// addText adds text, moving it to a new chunk or splitting it between words where it doesn't fit
+func (s *splitter) addText(text string) {
+	for text != "" {
+		space := s.space()
+
+		if len(text) <= space {
+			s.write(text)
+			return
+		}
+
+		// Move the text to a new chunk if it fits there, rather than splitting it
+		if s.hasContent() && len(text) <= s.limit-s.reopenLength()-s.reserve() {
+			s.flush()
+			continue
+		}
+
+		head, tail := splitWords(text, space)
+
+		if head == "" {
+			// Not even one character fits, which leaves nothing to do but start a new chunk
+			s.flush()
+			continue
+		}
+
+		s.write(head)
+		s.flush()
+
+		// Whitespace at a split outside of a code block would only start the next chunk
+		if s.fence == "" {
+			tail = strings.TrimLeftFunc(tail, unicode.IsSpace)
+		}
+
+		text = tail
+	}
+}
+
+func (s *splitter) write(text string) {
+	s.cur.WriteString(text)
+}
+
+// space is the room left in the current chunk, keeping room to close an open code block
+func (s *splitter) space() int {
+	return s.limit - s.cur.Len() - s.reserve()
+}
+
+// hasContent checks whether the current chunk has more than a re-opened code block
+func (s *splitter) hasContent() bool {
+	return s.cur.Len() > s.reopened
+}
+
+// reserve is the room kept at the end of a chunk to close an open code block
+func (s *splitter) reserve() int {
+	if s.fence == "" {
+		return 0
+	}
+
+	return closeLength(s.fence)
+}
+
+func (s *splitter) reopenLength() int {
+	if s.fence == "" {
+		return 0
+	}
+
+	return len(s.fence) + 1
+}
+
+// flush finishes the current chunk, closing any open code block, and starts the next one
+func (s *splitter) flush() {
+	if s.hasContent() {
+		chunk := s.cur.String()
+
+		if s.fence != "" {
+			if !strings.HasSuffix(chunk, "\n") {
+				chunk += "\n"
+			}
+
+			chunk += fenceCloser(s.fence)
+		} else {
+			chunk = strings.TrimRightFunc(chunk, unicode.IsSpace)
+		}
+
+		if strings.TrimSpace(chunk) != "" {
+			s.chunks = append(s.chunks, chunk)
+		}
+	}
+
+	s.cur.Reset()
+
+	// Re-opening the block must leave room for at least one character of it
+	if s.fence != "" && s.reopenLength()+s.reserve()+utf8.UTFMax > s.limit {
+		s.fence = ""
+	}
+
+	if s.fence != "" {
+		s.write(s.fence + "\n")
+	}
+
+	s.reopened = s.cur.Len()
+}
+
+// isOpeningFence checks whether a trimmed line opens a code block
+func isOpeningFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") && !strings.Contains(strings.TrimLeft(trimmed, "`"), "`")
+}
+
+// isClosingFence checks whether a trimmed line closes the code block opened by fence
+func isClosingFence(trimmed, fence string) bool {
+	return strings.Trim(trimmed, "`") == "" && len(trimmed) >= len(fenceCloser(fence))
+}
+
+// fenceCloser returns the backticks closing a code block opened by fence
+func fenceCloser(fence string) string {
+	return fence[:len(fence)-len(strings.TrimLeft(fence, "`"))]
+}
+
+// closeLength is the room needed to close a code block opened by fence, including the newline before it
+func closeLength(fence string) int {
+	return len(fenceCloser(fence)) + 1
+}
+
+// splitWords splits text so head has at most n bytes of whole characters,
+// at the last whitespace if there is one
+func splitWords(text string, n int) (head, tail string) {
+	end := 0
+
+	for end < len(text) {
+		_, size := utf8.DecodeRuneInString(text[end:])
+
+		if end+size > n {
+			break
+		}
+
+		end += size
+	}
+
+	head = text[:end]
+
+	// Split at whitespace, unless the text already breaks between words here
+	if next, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && unicode.IsSpace(next) {
+		return head, text[end:]
+	}
+
+	if idx := strings.LastIndexFunc(head, unicode.IsSpace); idx > 0 && end < len(text) {
+		_, size := utf8.DecodeRuneInString(head[idx:])
+		head = head[:idx+size]
+	}
+
+	return head, text[len(head):]
+}
@@ -0,0 +1,143 @@
+package valour
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// checkChunks fails if any chunk is over the limit or isn't valid UTF-8
+func checkChunks(t *testing.T, content string, limit int, chunks []string) {
+	t.Helper()
+
+	if limit <= 0 {
+		limit = MaxMessageLength
+	}
+
+	for _, chunk := range chunks {
+		if len(chunk) > max(limit, utf8.UTFMax) {
+			t.Fatalf("SplitContent(%q, %d) chunk %q is %d bytes", content, limit, chunk, len(chunk))
+		}
+
+		if !utf8.ValidString(chunk) {
+			t.Fatalf("SplitContent(%q, %d) chunk %q splits a character", content, limit, chunk)
+		}
+	}
+}
+
+func TestSplitContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		limit   int
+		want    []string
+	}{
+		{
+			name:    "fits",
+			content: "hello world",
+			limit:   20,
+			want:    []string{"hello world"},
+		},
+		{
+			name:    "default limit",
+			content: strings.Repeat("a", MaxMessageLength),
+			want:    []string{strings.Repeat("a", MaxMessageLength)},
+		},
+		{
+			name:    "lines",
+			content: "aaa\nbbb\nccc",
+			limit:   8,
+			want:    []string{"aaa\nbbb", "ccc"},
+		},
+		{
+			name:    "words",
+			content: "hello world foo",
+			limit:   11,
+			want:    []string{"hello world", "foo"},
+		},
+		{
+			name:    "words before limit",
+			content: "one two three",
+			limit:   10,
+			want:    []string{"one two", "three"},
+		},
+		{
+			name:    "long word",
+			content: "abcdefghij",
+			limit:   4,
+			want:    []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:    "multi-byte characters",
+			content: "日本語テキスト",
+			limit:   7,
+			want:    []string{"日本", "語テ", "キス", "ト"},
+		},
+		{
+			name:    "limit below a character",
+			content: "😀😀",
+			limit:   1,
+			want:    []string{"😀", "😀"},
+		},
+		{
+			name:    "code block",
+			content: "```go\nline1\nline2\n```",
+			limit:   20,
+			want:    []string{"```go\nline1\n```", "```go\nline2\n```"},
+		},
+		{
+			name:    "text around a code block",
+			content: "before\n```\ncode\n```\nafter",
+			limit:   16,
+			want:    []string{"before", "```\ncode\n```", "after"},
+		},
+		{
+			name:    "code block too small to re-open",
+			content: "```go\n" + strings.Repeat("x", 12),
+			limit:   12,
+			want:    []string{"```go", strings.Repeat("x", 12)},
+		},
+		{
+			name:    "fence in the middle of a line",
+			content: "text ```go code``` more words here and there",
+			limit:   8,
+			want:    []string{"text", "```go", "code```", "more", "words", "here and", "there"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitContent(tt.content, tt.limit)
+
+			checkChunks(t, tt.content, tt.limit, got)
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("SplitContent(%q, %d) = %q, want %q", tt.content, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitContentLimit(t *testing.T) {
+	pieces := []string{
+		"a", "word", " ", "\n", "```", "```go\n", "\n```\n", "````\n", "x ```y",
+		"é", "日本", "😀", "longwordlongwordlongword",
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	for range 20000 {
+		var b strings.Builder
+
+		for range r.Intn(40) {
+			b.WriteString(pieces[r.Intn(len(pieces))])
+		}
+
+		content := b.String()
+		limit := 1 + r.Intn(30)
+
+		checkChunks(t, content, limit, SplitContent(content, limit))
+	}
+}
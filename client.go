@@ -13,13 +13,15 @@ type Client interface {
 	Channels
 	Nodes
 	Roles
+	Members
+	Bans
+	PermissionsNodes
+	Invites
+	Friends
+	Users
+	Emojis
 
 	JoinAllChannels(ctx context.Context) error
-
-	Me() (*User, error)
-	MyMember(planetID PlanetID) (*Member, error)
-	Member(id MemberID) (*Member, error)
-	MemberByUser(planetID PlanetID, id UserID) (*Member, error)
 }
 
 type Nodes interface {
@@ -0,0 +1,101 @@
+package valour
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is an RGB color, serialized by the API as a hex string such as "#ff8800".
+//
+// A color decoded from JSON remembers what it was decoded from, and encodes back to exactly that
+// while R, G and B are unchanged. Unset, invalid and translucent colors sent by the API therefore
+// survive being sent back, such as when a role or profile is edited.
+type Color struct {
+	R, G, B uint8
+
+	// raw is the JSON the color was decoded from, and decoded the value it decoded to
+	raw     string
+	decoded uint32
+}
+
+// ParseColor parses a hex color, with or without a leading #, in the long (#ff8800) or short (#f80) form.
+// An alpha component (#ff8800cc or #f80c) is accepted and ignored.
+func ParseColor(s string) (Color, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+
+	switch len(hex) {
+	case 3, 4:
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	case 6, 8:
+		hex = hex[:6]
+	default:
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	return ColorFromInt(uint32(v)), nil
+}
+
+// ColorFromInt creates a color from a 0xRRGGBB value
+func ColorFromInt(v uint32) Color {
+	return Color{
+		R: uint8(v >> 16),
+		G: uint8(v >> 8),
+		B: uint8(v),
+	}
+}
+
+// Int returns the color as a 0xRRGGBB value
+func (c Color) Int() uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
+
+// String returns the color in the #rrggbb form
+func (c Color) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// MarshalJSON encodes the color as #rrggbb, or as it was decoded if it hasn't been changed since
+func (c Color) MarshalJSON() ([]byte, error) {
+	if c.raw != "" && c.Int() == c.decoded {
+		return []byte(c.raw), nil
+	}
+
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON decodes a hex color. Null, empty and invalid colors decode as black rather than
+// failing, so a bad color doesn't prevent the rest of an object from being read, and are
+// encoded back unchanged.
+func (c *Color) UnmarshalJSON(b []byte) error {
+	var s *string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	var color Color
+
+	if s != nil {
+		color, _ = ParseColor(*s)
+	}
+
+	color.raw = string(b)
+	color.decoded = color.Int()
+
+	*c = color
+
+	return nil
+}
+
+// Equal checks whether two colors have the same RGB value, regardless of how they were decoded
+func (c Color) Equal(other Color) bool {
+	return c.Int() == other.Int()
+}
@@ -0,0 +1,123 @@
+package valour
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint32
+		err  bool
+	}{
+		{in: "#ff8800", want: 0xff8800},
+		{in: "ff8800", want: 0xff8800},
+		{in: "#f80", want: 0xff8800},
+		{in: "#ff8800cc", want: 0xff8800},
+		{in: "#f80c", want: 0xff8800},
+		{in: "", err: true},
+		{in: "#ff88", want: 0xffff88},
+		{in: "#gg8800", err: true},
+		{in: "#ff88001", err: true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseColor(tt.in)
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseColor(%q) = %s, want error", tt.in, c)
+			}
+
+			continue
+		}
+
+		if err != nil || c.Int() != tt.want {
+			t.Errorf("ParseColor(%q) = %06x, %v, want %06x", tt.in, c.Int(), err, tt.want)
+		}
+	}
+}
+
+func TestColorRoundTrip(t *testing.T) {
+	tests := []string{
+		`"#ff8800"`,
+		`"#FF8800"`,
+		`"#f80"`,
+		`"#ff880080"`,
+		`""`,
+		`null`,
+		`"not a color"`,
+	}
+
+	for _, in := range tests {
+		var c Color
+
+		if err := json.Unmarshal([]byte(in), &c); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", in, err)
+		}
+
+		out, err := json.Marshal(c)
+
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", in, err)
+		}
+
+		if string(out) != in {
+			t.Errorf("round trip of %s = %s", in, out)
+		}
+	}
+}
+
+func TestColorChangedIsReencoded(t *testing.T) {
+	var c Color
+
+	if err := json.Unmarshal([]byte(`""`), &c); err != nil {
+		t.Fatal(err)
+	}
+
+	c.R = 0xff
+
+	out, _ := json.Marshal(c)
+
+	if string(out) != `"#ff0000"` {
+		t.Fatalf("changed color = %s, want \"#ff0000\"", out)
+	}
+
+	out, _ = json.Marshal(ColorFromInt(0))
+
+	if string(out) != `"#000000"` {
+		t.Fatalf("new black color = %s, want \"#000000\"", out)
+	}
+}
+
+func TestRoleColorRoundTrip(t *testing.T) {
+	var role Role
+
+	if err := json.Unmarshal([]byte(`{"id":1,"name":"role","color":""}`), &role); err != nil {
+		t.Fatal(err)
+	}
+
+	// A name only edit, as EditRole applies it, keeps the unset color
+	NewRoleUpdate().Name("renamed").Apply(&role)
+
+	b, err := json.Marshal(role)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fields["color"]) != `""` {
+		t.Fatalf("color = %s, want the original empty string", fields["color"])
+	}
+
+	if string(fields["name"]) != `"renamed"` {
+		t.Fatalf("name = %s, want \"renamed\"", fields["name"])
+	}
+}
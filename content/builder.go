@@ -0,0 +1,201 @@
+package content
+
+import (
+	"strings"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// markdownEscaper escapes the characters markdown treats as formatting.
+// Mention markup is broken up with a zero width space, so text can't mention or ping anyone.
+var markdownEscaper = strings.NewReplacer(
+	mentionStart, mentionStart+"\u200b",
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+	"#", `\#`,
+	"[", `\[`,
+	"]", `\]`,
+	"(", `\(`,
+	")", `\)`,
+	"<", `\<`,
+)
+
+// Escape escapes markdown formatting and mention markup in s, so it's displayed as written
+func Escape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func MentionUser(id valour.UserID) string {
+	return Mention{Type: UserMention, ID: valour.Snowflake(id)}.String()
+}
+
+func MentionMember(id valour.MemberID) string {
+	return Mention{Type: MemberMention, ID: valour.Snowflake(id)}.String()
+}
+
+func MentionRole(id valour.RoleID) string {
+	return Mention{Type: RoleMention, ID: valour.Snowflake(id)}.String()
+}
+
+func MentionChannel(id valour.ChannelID) string {
+	return Mention{Type: ChannelMention, ID: valour.Snowflake(id)}.String()
+}
+
+// Emoji returns the markup of a planet's custom emoji
+func Emoji(id valour.EmojiID) string {
+	return Mention{Type: EmojiMention, ID: valour.Snowflake(id)}.String()
+}
+
+func Bold(s string) string {
+	return "**" + s + "**"
+}
+
+func Italic(s string) string {
+	return "*" + s + "*"
+}
+
+func Underline(s string) string {
+	return "__" + s + "__"
+}
+
+func Strikethrough(s string) string {
+	return "~~" + s + "~~"
+}
+
+func Spoiler(s string) string {
+	return "||" + s + "||"
+}
+
+// Code formats s as inline code, using a longer delimiter if s contains backticks
+func Code(s string) string {
+	fence := "`"
+
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+
+	// A space keeps a leading or trailing backtick from joining the delimiter
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	return fence + s + fence
+}
+
+// CodeBlock formats s as a fenced code block, with optional syntax highlighting for lang
+func CodeBlock(lang, s string) string {
+	fence := "```"
+
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+
+	return fence + lang + "\n" + strings.TrimSuffix(s, "\n") + "\n" + fence
+}
+
+// Quote formats every line of s as a block quote
+func Quote(s string) string {
+	return "> " + strings.ReplaceAll(s, "\n", "\n> ")
+}
+
+// Link formats a link with text, escaping the text
+func Link(text, url string) string {
+	return "[" + Escape(text) + "](" + url + ")"
+}
+
+// Builder builds message content, escaping text unless it's added with Raw
+type Builder struct {
+	b strings.Builder
+}
+
+// Text adds escaped text
+func (b *Builder) Text(s string) *Builder {
+	b.b.WriteString(Escape(s))
+	return b
+}
+
+// Raw adds text as written, keeping any markdown or mention markup
+func (b *Builder) Raw(s string) *Builder {
+	b.b.WriteString(s)
+	return b
+}
+
+func (b *Builder) Line() *Builder {
+	b.b.WriteString("\n")
+	return b
+}
+
+func (b *Builder) User(id valour.UserID) *Builder {
+	return b.Raw(MentionUser(id))
+}
+
+func (b *Builder) Member(id valour.MemberID) *Builder {
+	return b.Raw(MentionMember(id))
+}
+
+func (b *Builder) Role(id valour.RoleID) *Builder {
+	return b.Raw(MentionRole(id))
+}
+
+func (b *Builder) Channel(id valour.ChannelID) *Builder {
+	return b.Raw(MentionChannel(id))
+}
+
+func (b *Builder) Emoji(id valour.EmojiID) *Builder {
+	return b.Raw(Emoji(id))
+}
+
+// Bold adds escaped bold text
+func (b *Builder) Bold(s string) *Builder {
+	return b.Raw(Bold(Escape(s)))
+}
+
+// Italic adds escaped italic text
+func (b *Builder) Italic(s string) *Builder {
+	return b.Raw(Italic(Escape(s)))
+}
+
+// Underline adds escaped underlined text
+func (b *Builder) Underline(s string) *Builder {
+	return b.Raw(Underline(Escape(s)))
+}
+
+// Strikethrough adds escaped struck through text
+func (b *Builder) Strikethrough(s string) *Builder {
+	return b.Raw(Strikethrough(Escape(s)))
+}
+
+// Spoiler adds escaped text hidden behind a spoiler
+func (b *Builder) Spoiler(s string) *Builder {
+	return b.Raw(Spoiler(Escape(s)))
+}
+
+func (b *Builder) Code(s string) *Builder {
+	return b.Raw(Code(s))
+}
+
+func (b *Builder) CodeBlock(lang, s string) *Builder {
+	return b.Raw(CodeBlock(lang, s))
+}
+
+// Quote adds escaped text as a block quote on its own lines
+func (b *Builder) Quote(s string) *Builder {
+	return b.Raw(Quote(Escape(s)) + "\n")
+}
+
+func (b *Builder) Link(text, url string) *Builder {
+	return b.Raw(Link(text, url))
+}
+
+func (b *Builder) Len() int {
+	return b.b.Len()
+}
+
+func (b *Builder) String() string {
+	return b.b.String()
+}
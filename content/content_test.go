@@ -0,0 +1,85 @@
+package content
+
+import (
+	"slices"
+	"testing"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+func TestEscapeNeutralisesMentions(t *testing.T) {
+	tests := []string{
+		MentionUser(123),
+		MentionMember(123),
+		MentionRole(123),
+		MentionChannel(123),
+		Emoji(123),
+		"hi " + MentionRole(5) + " and " + MentionUser(6),
+	}
+
+	for _, in := range tests {
+		if mentions := ParseMentions(Escape(in)); len(mentions) != 0 {
+			t.Errorf("Escape(%q) still contains mentions: %v", in, mentions)
+		}
+
+		var b Builder
+
+		if mentions := ParseMentions(b.Text(in).String()); len(mentions) != 0 {
+			t.Errorf("Builder.Text(%q) still contains mentions: %v", in, mentions)
+		}
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := map[string]string{
+		"**bold**":         `\*\*bold\*\*`,
+		"[x](javascript:)": `\[x\]\(javascript:\)`,
+		"<b>":              `\<b\>`,
+		`a\b`:              `a\\b`,
+		"plain":            "plain",
+	}
+
+	for in, want := range tests {
+		if got := Escape(in); got != want {
+			t.Errorf("Escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuilderMentions(t *testing.T) {
+	var b Builder
+
+	content := b.Text("hey ").User(1).Text(" ").Role(2).Text(" ").Emoji(3).String()
+
+	got := ParseMentions(content)
+
+	want := []Mention{
+		{Type: UserMention, ID: 1},
+		{Type: RoleMention, ID: 2},
+		{Type: EmojiMention, ID: 3},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("mentions = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i].Type != want[i].Type || got[i].ID != want[i].ID {
+			t.Errorf("mention %d = %v, want %v", i, got[i], want[i])
+		}
+
+		if content[got[i].Start:got[i].End] != got[i].String() {
+			t.Errorf("mention %d offsets don't cover its markup", i)
+		}
+	}
+}
+
+func TestEmojiMatchesReactionMarkup(t *testing.T) {
+	if Emoji(42) != valour.CustomEmoji(42).String() {
+		t.Fatalf("Emoji(42) = %q, reaction markup is %q", Emoji(42), valour.CustomEmoji(42).String())
+	}
+
+	if ids := Emojis("a " + valour.CustomEmoji(42).String()); !slices.Equal(ids, []valour.EmojiID{42}) {
+		t.Fatalf("Emojis = %v, want [42]", ids)
+	}
+}
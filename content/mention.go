@@ -0,0 +1,172 @@
+// Package content parses and builds Valour message content, including mention markup and markdown.
+package content
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// MentionType is the kind of object a mention refers to
+type MentionType int
+
+const (
+	UserMention MentionType = iota
+	MemberMention
+	RoleMention
+	ChannelMention
+	EmojiMention
+)
+
+// mentionPrefixes are the markup before each mention type's ID, as in «@m-123».
+// Custom emojis use the same markup in content as in reactions.
+var mentionPrefixes = map[MentionType]string{
+	UserMention:    "«@u-",
+	MemberMention:  "«@m-",
+	RoleMention:    "«@r-",
+	ChannelMention: "«#c-",
+	EmojiMention:   valour.CustomEmojiPrefix,
+}
+
+// mentionSuffix ends the markup of every mention type
+const mentionSuffix = valour.CustomEmojiSuffix
+
+// mentionStart is the first character of every mention's markup
+const mentionStart = "«"
+
+var mentionPattern = func() *regexp.Regexp {
+	prefixes := make([]string, 0, len(mentionPrefixes))
+
+	for _, p := range mentionPrefixes {
+		prefixes = append(prefixes, regexp.QuoteMeta(p))
+	}
+
+	return regexp.MustCompile("(" + strings.Join(prefixes, "|") + `)(\d+)` + regexp.QuoteMeta(mentionSuffix))
+}()
+
+// Mention is a mention found in message content
+type Mention struct {
+	Type MentionType
+	ID   valour.Snowflake
+
+	// Start and End are the byte offsets of the mention's markup in the content
+	Start, End int
+}
+
+func (m Mention) UserID() valour.UserID {
+	return valour.UserID(m.ID)
+}
+
+func (m Mention) MemberID() valour.MemberID {
+	return valour.MemberID(m.ID)
+}
+
+func (m Mention) RoleID() valour.RoleID {
+	return valour.RoleID(m.ID)
+}
+
+func (m Mention) ChannelID() valour.ChannelID {
+	return valour.ChannelID(m.ID)
+}
+
+func (m Mention) EmojiID() valour.EmojiID {
+	return valour.EmojiID(m.ID)
+}
+
+// String returns the markup of the mention
+func (m Mention) String() string {
+	return mentionPrefixes[m.Type] + m.ID.String() + mentionSuffix
+}
+
+// ParseMentions returns every mention in content, in the order they appear
+func ParseMentions(content string) []Mention {
+	var mentions []Mention
+
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		id, err := strconv.ParseUint(content[loc[4]:loc[5]], 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		mentions = append(mentions, Mention{
+			Type:  mentionType(content[loc[2]:loc[3]]),
+			ID:    valour.Snowflake(id),
+			Start: loc[0],
+			End:   loc[1],
+		})
+	}
+
+	return mentions
+}
+
+// Members returns the unique members mentioned in content
+func Members(content string) []valour.MemberID {
+	return mentioned(content, MemberMention, Mention.MemberID)
+}
+
+// Users returns the unique users mentioned in content
+func Users(content string) []valour.UserID {
+	return mentioned(content, UserMention, Mention.UserID)
+}
+
+// Roles returns the unique roles mentioned in content
+func Roles(content string) []valour.RoleID {
+	return mentioned(content, RoleMention, Mention.RoleID)
+}
+
+// Channels returns the unique channels mentioned in content
+func Channels(content string) []valour.ChannelID {
+	return mentioned(content, ChannelMention, Mention.ChannelID)
+}
+
+// Emojis returns the unique custom emojis used in content
+func Emojis(content string) []valour.EmojiID {
+	return mentioned(content, EmojiMention, Mention.EmojiID)
+}
+
+// MentionsMember checks whether a member is mentioned directly, or through their user
+func MentionsMember(content string, member valour.Member) bool {
+	for _, m := range ParseMentions(content) {
+		if (m.Type == MemberMention && m.MemberID() == member.ID) || (m.Type == UserMention && m.UserID() == member.UserID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mentioned[V comparable](content string, t MentionType, id func(Mention) V) []V {
+	var ids []V
+
+	seen := make(map[V]struct{})
+
+	for _, m := range ParseMentions(content) {
+		if m.Type != t {
+			continue
+		}
+
+		v := id(m)
+
+		if _, ok := seen[v]; ok {
+			continue
+		}
+
+		seen[v] = struct{}{}
+		ids = append(ids, v)
+	}
+
+	return ids
+}
+
+func mentionType(prefix string) MentionType {
+	for t, p := range mentionPrefixes {
+		if p == prefix {
+			return t
+		}
+	}
+
+	return UserMention
+}
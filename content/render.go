@@ -0,0 +1,80 @@
+package content
+
+import (
+	"strings"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// Resolver looks up the objects mentions refer to.
+// Both valour.Client and state.State implement it, state.State answering from its cache where it can.
+type Resolver interface {
+	User(id valour.UserID) (*valour.User, error)
+	Member(id valour.MemberID) (*valour.Member, error)
+	Role(planetID valour.PlanetID, roleID valour.RoleID) (*valour.Role, error)
+	Channel(planetID valour.PlanetID, channelID valour.ChannelID) (*valour.Channel, error)
+	Emoji(planetID valour.PlanetID, emojiID valour.EmojiID) (*valour.Emoji, error)
+}
+
+// Render replaces the mentions in content with readable names, such as @nickname, @role, #channel and :emoji:.
+// Mentions that can't be resolved are rendered with their ID.
+func Render(r Resolver, planetID valour.PlanetID, content string) string {
+	mentions := ParseMentions(content)
+
+	if len(mentions) == 0 {
+		return content
+	}
+
+	var b strings.Builder
+
+	last := 0
+
+	for _, m := range mentions {
+		b.WriteString(content[last:m.Start])
+		b.WriteString(renderMention(r, planetID, m))
+		last = m.End
+	}
+
+	b.WriteString(content[last:])
+
+	return b.String()
+}
+
+// RenderMessage renders a message's content in the message's planet
+func RenderMessage(r Resolver, msg valour.Message) string {
+	return Render(r, msg.PlanetID, msg.Content)
+}
+
+func renderMention(r Resolver, planetID valour.PlanetID, m Mention) string {
+	switch m.Type {
+	case UserMention:
+		if user, err := r.User(m.UserID()); err == nil {
+			return "@" + user.Name
+		}
+	case MemberMention:
+		if member, err := r.Member(m.MemberID()); err == nil {
+			return "@" + member.DisplayName()
+		}
+	case RoleMention:
+		if role, err := r.Role(planetID, m.RoleID()); err == nil {
+			return "@" + role.Name
+		}
+	case ChannelMention:
+		if channel, err := r.Channel(planetID, m.ChannelID()); err == nil {
+			return "#" + channel.Name
+		}
+	case EmojiMention:
+		if emoji, err := r.Emoji(planetID, m.EmojiID()); err == nil {
+			return ":" + emoji.Name + ":"
+		}
+	}
+
+	switch m.Type {
+	case ChannelMention:
+		return "#" + m.ID.String()
+	case EmojiMention:
+		return ":" + m.ID.String() + ":"
+	default:
+		return "@" + m.ID.String()
+	}
+}
@@ -0,0 +1,77 @@
+package valour
+
+import (
+	"errors"
+	"time"
+
+	cmap "github.com/orcaman/concurrent-map/v2"
+)
+
+// echoTTL is how long a sent message's fingerprint is remembered while waiting for its realtime echo
+const echoTTL = time.Minute
+
+// ErrNotDelivered is returned with the sent message when SendMessageData.WaitForDelivery passes
+// without the message arriving in realtime
+var ErrNotDelivered = errors.New("message was not delivered in realtime")
+
+// echoTracker remembers the fingerprints of our sent messages, so their realtime echoes can be recognized
+type echoTracker struct {
+	pending cmap.ConcurrentMap[string, chan *Message]
+}
+
+func newEchoTracker() *echoTracker {
+	return &echoTracker{
+		pending: cmap.New[chan *Message](),
+	}
+}
+
+// track starts waiting for a fingerprint's echo, returning a channel that receives the echoed message
+func (t *echoTracker) track(fingerprint string) <-chan *Message {
+	ch := make(chan *Message, 1)
+
+	t.pending.Set(fingerprint, ch)
+
+	// Messages to channels we haven't joined never echo
+	time.AfterFunc(echoTTL, func() {
+		t.forget(fingerprint)
+	})
+
+	return ch
+}
+
+func (t *echoTracker) forget(fingerprint string) {
+	t.pending.RemoveCb(fingerprint, func(_ string, _ chan *Message, _ bool) bool {
+		return true
+	})
+}
+
+// receive checks whether a message is the echo of one of our sends, notifying anyone waiting for it
+func (t *echoTracker) receive(m *Message) bool {
+	if m.Fingerprint == "" {
+		return false
+	}
+
+	ch, ok := t.pending.Pop(m.Fingerprint)
+
+	if !ok {
+		return false
+	}
+
+	ch <- m
+
+	return true
+}
+
+// Call marks message events echoing our own sends before dispatching events to handlers
+func (n *Node) Call(event interface{}) {
+	if ev, ok := event.(*MessageCreateEvent); ok {
+		ev.IsOwnEcho = n.echoes().receive(&ev.Message)
+	}
+
+	n.Handler.Call(event)
+}
+
+// echoes returns the fingerprint tracker, shared by all nodes through the primary node
+func (n *Node) echoes() *echoTracker {
+	return n.primaryNode().echoTracker
+}
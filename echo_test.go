@@ -0,0 +1,145 @@
+package valour
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// echoServer handles message sends, calling echo with each sent message before responding
+func echoServer(t *testing.T, echo func(m Message)) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/messages", func(w http.ResponseWriter, r *http.Request) {
+		var send SendMessageData
+
+		if err := json.NewDecoder(r.Body).Decode(&send); err != nil {
+			t.Errorf("decoding send: %v", err)
+		}
+
+		m := Message{ID: 1, ChannelID: send.ChannelID, Content: send.Content, Fingerprint: send.Fingerprint}
+
+		if echo != nil {
+			echo(m)
+		}
+
+		writeJSON(w, m)
+	})
+
+	return mux
+}
+
+func TestEchoBeforeResponse(t *testing.T) {
+	var (
+		node   *Node
+		echoed *MessageCreateEvent
+	)
+
+	node = newTestNode(t, echoServer(t, func(m Message) {
+		// The realtime echo beats the HTTP response
+		echoed = &MessageCreateEvent{Message: m}
+		node.Call(echoed)
+	}))
+
+	m, err := node.SendMessageComplex(NullPlanetID, 2, SendMessageData{Content: "hi", WaitForDelivery: time.Second})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !echoed.IsOwnEcho || echoed.Fingerprint != m.Fingerprint {
+		t.Fatalf("echo = %+v, want our own echo of %q", echoed, m.Fingerprint)
+	}
+
+	// Another message with the same fingerprint isn't an echo of ours
+	repeated := &MessageCreateEvent{Message: echoed.Message}
+	node.Call(repeated)
+
+	if repeated.IsOwnEcho {
+		t.Fatal("a fingerprint was matched twice")
+	}
+
+	other := &MessageCreateEvent{Message: Message{ID: 2, Fingerprint: "someone else"}}
+	node.Call(other)
+
+	if other.IsOwnEcho {
+		t.Fatal("another client's message was taken for our echo")
+	}
+}
+
+func TestEchoNotDelivered(t *testing.T) {
+	node := newTestNode(t, echoServer(t, nil))
+
+	m, err := node.SendMessageComplex(NullPlanetID, 2, SendMessageData{Content: "hi", WaitForDelivery: 20 * time.Millisecond})
+
+	if !errors.Is(err, ErrNotDelivered) {
+		t.Fatalf("err = %v, want ErrNotDelivered", err)
+	}
+
+	if m == nil || m.Content != "hi" {
+		t.Fatalf("message = %+v, want the sent message alongside ErrNotDelivered", m)
+	}
+
+	if pending := node.echoes().pending.Count(); pending != 0 {
+		t.Fatalf("%d fingerprints still tracked after timing out", pending)
+	}
+
+	// A late echo is no longer recognised
+	late := &MessageCreateEvent{Message: *m}
+	node.Call(late)
+
+	if late.IsOwnEcho {
+		t.Fatal("a forgotten fingerprint was matched")
+	}
+}
+
+func TestEchoForgottenOnFailedSend(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/messages", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	node := newTestNode(t, mux)
+
+	if _, err := node.SendMessageComplex(NullPlanetID, 2, SendMessageData{Content: "hi"}); err == nil {
+		t.Fatal("expected the send to fail")
+	}
+
+	if pending := node.echoes().pending.Count(); pending != 0 {
+		t.Fatalf("%d fingerprints still tracked after a failed send", pending)
+	}
+}
+
+func TestEchoThroughChildNode(t *testing.T) {
+	var fingerprint string
+
+	primary := newTestNode(t, echoServer(t, func(m Message) {
+		fingerprint = m.Fingerprint
+	}))
+
+	child, err := NewNode(primary.baseAddress, "child", "token")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child.Primary = primary
+
+	if child.echoes() != primary.echoes() {
+		t.Fatal("child node doesn't share the primary node's echo tracker")
+	}
+
+	// Sent through the primary node, while the realtime echo arrives on the child's connection
+	if _, err := primary.SendMessageComplex(NullPlanetID, 2, SendMessageData{Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	echo := &MessageCreateEvent{Message: Message{ID: 1, Fingerprint: fingerprint}}
+	child.Call(echo)
+
+	if !echo.IsOwnEcho {
+		t.Fatal("child node didn't recognise the echo of a message sent through the primary node")
+	}
+}
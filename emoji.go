@@ -1,10 +1,95 @@
 package valour
 
-import "time"
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Emojis interface {
+	Emoji(planetID PlanetID, emojiID EmojiID) (*Emoji, error)
+	Emojis(planetID PlanetID) ([]Emoji, error)
+	CreateEmoji(planetID PlanetID, name, fileName string, r io.Reader, size int64) (*Emoji, error)
+	DeleteEmoji(planetID PlanetID, emojiID EmojiID) error
+}
 
 type Emoji struct {
 	ID            EmojiID   `json:"id"`
+	PlanetID      PlanetID  `json:"planetId"`
 	CreatorUserID UserID    `json:"creatorUserId"`
 	Name          string    `json:"name"`
 	CreatedAt     time.Time `json:"createdAt"`
 }
+
+// Emoji retrieves a planet's custom emoji
+func (n *Node) Emoji(planetID PlanetID, emojiID EmojiID) (*Emoji, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var emoji Emoji
+
+	if err := node.requestJSON(http.MethodGet, emojiID.Route(), nil, &emoji); err != nil {
+		return nil, err
+	}
+
+	return &emoji, nil
+}
+
+// Emojis retrieves a planet's custom emojis
+func (n *Node) Emojis(planetID PlanetID) ([]Emoji, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var emojis []Emoji
+
+	if err := node.requestJSON(http.MethodGet, planetID.Route("emojis"), nil, &emojis); err != nil {
+		return nil, err
+	}
+
+	return emojis, nil
+}
+
+// CreateEmoji uploads a png, jpeg or gif image as a planet's custom emoji
+func (n *Node) CreateEmoji(planetID PlanetID, name, fileName string, r io.Reader, size int64) (*Emoji, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := make(url.Values)
+	q.Set("name", name)
+
+	upload, err := node.uploadImage("upload/emoji/"+planetID.String()+"?"+q.Encode(), fileName, r, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var emoji Emoji
+
+	if err := json.Unmarshal(upload.Body, &emoji); err != nil {
+		return nil, err
+	}
+
+	return &emoji, nil
+}
+
+// DeleteEmoji deletes a planet's custom emoji
+func (n *Node) DeleteEmoji(planetID PlanetID, emojiID EmojiID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, emojiID.Route(), nil)
+}
@@ -28,8 +28,27 @@ type ChannelDeleteEvent struct {
 	Channel
 }
 
+type RoleUpdateEvent struct {
+	Role
+}
+
+type RoleDeleteEvent struct {
+	Role
+}
+
+type PermissionsNodeUpdateEvent struct {
+	PermissionsNode
+}
+
+type PermissionsNodeDeleteEvent struct {
+	PermissionsNode
+}
+
 type MessageCreateEvent struct {
 	Message
+
+	// IsOwnEcho is set when the message was sent by this client, matched by its fingerprint
+	IsOwnEcho bool `json:"-"`
 }
 
 type MessageEditEvent struct {
@@ -52,6 +71,38 @@ type PlanetMemberDelete struct {
 	Member
 }
 
+type FriendUpdateEvent struct {
+	UserFriend
+}
+
+type FriendDeleteEvent struct {
+	UserFriend
+}
+
+type EmojiUpdateEvent struct {
+	Emoji
+}
+
+type EmojiDeleteEvent struct {
+	Emoji
+}
+
+type PlanetBanUpdate struct {
+	Ban
+}
+
+type PlanetBanDelete struct {
+	Ban
+}
+
+type MessagePinEvent struct {
+	Message
+}
+
+type MessageUnpinEvent struct {
+	Message
+}
+
 type MessageReactionEvent struct {
 	MessageID MessageID `json:"messageId"`
 	UserID    UserID    `json:"authorUserId"`
@@ -52,5 +52,10 @@ func main() {
 }
 
 func messageCreateHandler(e *valour.MessageCreateEvent) {
+	// Ignore messages we sent ourselves
+	if e.IsOwnEcho {
+		return
+	}
+
 	log.Info("Message created in planet " + e.PlanetID.String() + " by " + e.AuthorID.String() + ": " + e.Content)
 }
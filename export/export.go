@@ -0,0 +1,231 @@
+// Package export writes a channel's message history to JSON Lines, Markdown or HTML.
+package export
+
+import (
+	"context"
+	"io"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// Format is the output format of an export
+type Format int
+
+const (
+	// FormatJSONLines writes one JSON encoded message per line
+	FormatJSONLines Format = iota
+	// FormatMarkdown writes a readable Markdown transcript
+	FormatMarkdown
+	// FormatHTML writes a single self-contained HTML page
+	FormatHTML
+)
+
+// Exporter streams channel history to a writer, oldest message first.
+// An Exporter caches resolved author names and isn't safe for concurrent use.
+type Exporter struct {
+	client valour.Client
+	format Format
+	after  valour.MessageID
+
+	authors map[valour.Snowflake]string
+}
+
+type Option func(e *Exporter)
+
+// WithFormat sets the output format, defaulting to FormatJSONLines
+func WithFormat(format Format) Option {
+	return func(e *Exporter) {
+		e.format = format
+	}
+}
+
+// WithResume continues a previous export, only writing messages after the last exported message.
+// JSON Lines and Markdown output can be appended to the previous file, HTML output is a new page.
+func WithResume(after valour.MessageID) Option {
+	return func(e *Exporter) {
+		e.after = after
+	}
+}
+
+func New(client valour.Client, opts ...Option) *Exporter {
+	e := &Exporter{
+		client:  client,
+		authors: make(map[valour.Snowflake]string),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Result describes a finished or interrupted export
+type Result struct {
+	// Messages is the number of messages written
+	Messages int
+
+	// LastMessageID is the last message written, to be passed to WithResume.
+	// It is the resume point given to the exporter if nothing was written.
+	LastMessageID valour.MessageID
+}
+
+// entry is a message with the details resolved for output
+type entry struct {
+	valour.Message
+
+	Author    string
+	Reply     *reply
+	Reactions []reactionCount
+}
+
+type reply struct {
+	ID      valour.MessageID
+	Author  string
+	Excerpt string
+}
+
+type reactionCount struct {
+	Emoji string
+	Count int
+}
+
+// formatter writes entries in an output format
+type formatter interface {
+	begin(channel *valour.Channel, resumed bool) error
+	message(e *entry) error
+	end() error
+}
+
+// Export writes the history of a channel to w. Direct and group channels use valour.NullPlanetID.
+// The result is valid even when an error is returned, so an interrupted export can be resumed.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, planetID valour.PlanetID, channelID valour.ChannelID) (Result, error) {
+	result := Result{LastMessageID: e.after}
+
+	channel, err := e.client.Channel(planetID, channelID)
+
+	if err != nil {
+		return result, err
+	}
+
+	f := e.formatter(w)
+
+	if err := f.begin(channel, e.after.IsValid()); err != nil {
+		return result, err
+	}
+
+	for msg, err := range e.client.MessagesAfterIter(planetID, channelID, e.after) {
+		if err != nil {
+			return result, err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		// History is written oldest first, a message that isn't newer means the history is repeating
+		if msg.ID <= result.LastMessageID {
+			break
+		}
+
+		if err := f.message(e.entry(msg)); err != nil {
+			return result, err
+		}
+
+		result.Messages++
+		result.LastMessageID = msg.ID
+	}
+
+	return result, f.end()
+}
+
+func (e *Exporter) formatter(w io.Writer) formatter {
+	switch e.format {
+	case FormatMarkdown:
+		return &markdown{w: w}
+	case FormatHTML:
+		return &htmlPage{w: w}
+	default:
+		return &jsonLines{w: w}
+	}
+}
+
+func (e *Exporter) entry(msg valour.Message) *entry {
+	en := &entry{
+		Message: msg,
+		Author:  e.authorName(msg),
+	}
+
+	for _, r := range msg.ReactionSummaries(valour.NullUserID) {
+		en.Reactions = append(en.Reactions, reactionCount{
+			Emoji: r.Emoji.String(),
+			Count: r.Count,
+		})
+	}
+
+	parent := msg.ReplyTo
+
+	// The parent isn't always included, a deleted parent is left out
+	if parent == nil && msg.IsReply() {
+		parent, _ = e.client.Message(*msg.ReplyToID)
+	}
+
+	if parent != nil {
+		en.Reply = &reply{
+			ID:      parent.ID,
+			Author:  e.authorName(*parent),
+			Excerpt: excerpt(parent.Content, 100),
+		}
+	}
+
+	return en
+}
+
+// authorName resolves the name a message's author is displayed with, preferring their member nickname
+func (e *Exporter) authorName(msg valour.Message) string {
+	key := valour.Snowflake(msg.AuthorID)
+
+	if msg.MemberID.IsValid() {
+		key = valour.Snowflake(msg.MemberID)
+	}
+
+	if name, ok := e.authors[key]; ok {
+		return name
+	}
+
+	e.authors[key] = e.resolveAuthor(msg)
+
+	return e.authors[key]
+}
+
+func (e *Exporter) resolveAuthor(msg valour.Message) string {
+	if msg.MemberID.IsValid() {
+		if member, err := e.client.Member(msg.MemberID); err == nil {
+			return member.DisplayName()
+		}
+	}
+
+	if user, err := e.client.User(msg.AuthorID); err == nil {
+		return user.Name
+	}
+
+	return msg.AuthorID.String()
+}
+
+// excerpt shortens s to its first line, with at most n runes
+func excerpt(s string, n int) string {
+	r := []rune(s)
+
+	for i, c := range r {
+		if c == '\n' {
+			r = r[:i]
+			break
+		}
+	}
+
+	if len(r) > n {
+		return string(r[:n]) + "…"
+	}
+
+	return string(r)
+}
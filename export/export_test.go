@@ -0,0 +1,184 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"iter"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// fakeClient serves a fixed channel history.
+// Methods not used by the exporter panic through the nil embedded Client.
+type fakeClient struct {
+	valour.Client
+
+	channel  valour.Channel
+	messages []valour.Message
+	members  map[valour.MemberID]valour.Member
+	users    map[valour.UserID]valour.User
+}
+
+func (c *fakeClient) Channel(planetID valour.PlanetID, channelID valour.ChannelID) (*valour.Channel, error) {
+	return &c.channel, nil
+}
+
+func (c *fakeClient) MessagesAfterIter(planetID valour.PlanetID, channelID valour.ChannelID, index valour.MessageID) iter.Seq2[valour.Message, error] {
+	return func(yield func(valour.Message, error) bool) {
+		for _, m := range c.messages {
+			if m.ID > index && !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *fakeClient) Message(id valour.MessageID) (*valour.Message, error) {
+	for _, m := range c.messages {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func (c *fakeClient) Member(id valour.MemberID) (*valour.Member, error) {
+	if m, ok := c.members[id]; ok {
+		return &m, nil
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func (c *fakeClient) User(id valour.UserID) (*valour.User, error) {
+	if u, ok := c.users[id]; ok {
+		return &u, nil
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func testClient() *fakeClient {
+	sent := time.Date(2025, 3, 14, 15, 9, 0, 0, time.UTC)
+	edited := sent.Add(time.Hour)
+	nickname := "](javascript:alert(1)) **nick**"
+	parent := valour.MessageID(100)
+
+	return &fakeClient{
+		channel: valour.Channel{ID: 2, PlanetID: 1, Name: "general_chat", Description: "Talk *here*"},
+		messages: []valour.Message{
+			{
+				ID:        100,
+				AuthorID:  10,
+				MemberID:  20,
+				Content:   "Hello **world**\nsecond line",
+				TimeSent:  sent,
+				Reactions: []valour.Reaction{{Emoji: "👍", AuthorUserID: 11}, {Emoji: "👍", AuthorUserID: 12}},
+			},
+			{
+				ID:         101,
+				AuthorID:   11,
+				ReplyToID:  &parent,
+				Content:    "<b>reply</b> & more",
+				TimeSent:   sent.Add(time.Minute),
+				EditedTime: &edited,
+				Attachments: []valour.MessageAttachment{
+					{Location: "https://cdn.valour.gg/a (1).png", FileName: "a_[1].png"},
+				},
+			},
+		},
+		members: map[valour.MemberID]valour.Member{
+			20: {ID: 20, UserID: 10, Nickname: &nickname},
+		},
+		users: map[valour.UserID]valour.User{
+			11: {ID: 11, Name: "user_two"},
+		},
+	}
+}
+
+func TestExportGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+	}{
+		{"export.jsonl", FormatJSONLines},
+		{"export.md", FormatMarkdown},
+		{"export.html", FormatHTML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			result, err := New(testClient(), WithFormat(tt.format)).Export(context.Background(), &buf, 1, 2)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Messages != 2 || result.LastMessageID != 101 {
+				t.Fatalf("result = %+v, want 2 messages ending at 101", result)
+			}
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Fatalf("output differs from %s, run with -update to regenerate\ngot:\n%s", golden, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestExportResume(t *testing.T) {
+	var buf bytes.Buffer
+
+	result, err := New(testClient(), WithResume(100)).Export(context.Background(), &buf, 1, 2)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Messages != 1 || result.LastMessageID != 101 {
+		t.Fatalf("result = %+v, want 1 message ending at 101", result)
+	}
+}
+
+func TestExportStopsOnRepeatedHistory(t *testing.T) {
+	client := testClient()
+
+	// A history that repeats itself, as a server ignoring the cursor would return
+	client.messages = append(client.messages, client.messages...)
+
+	var buf bytes.Buffer
+
+	result, err := New(client).Export(context.Background(), &buf, 1, 2)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Messages != 2 {
+		t.Fatalf("wrote %d messages, want 2 without repeats", result.Messages)
+	}
+}
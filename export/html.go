@@ -0,0 +1,63 @@
+package export
+
+import (
+	"html/template"
+	"io"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// htmlPage writes a single page with inline styles, so the export can be opened without any other files
+type htmlPage struct {
+	w io.Writer
+}
+
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"time": formatTime,
+	"name": attachmentName,
+}).Parse(`{{define "begin"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { margin: 0; padding: 1.5rem; background: #1e1f22; color: #dbdee1; font-family: system-ui, sans-serif; line-height: 1.4; }
+h1 { margin-top: 0; }
+.message { padding: .5rem .75rem; border-radius: 4px; }
+.message:target { background: #3a3c43; }
+.author { font-weight: 600; color: #fff; }
+.time { color: #949ba4; font-size: .8rem; margin-left: .5rem; }
+.reply { color: #949ba4; font-size: .85rem; border-left: 3px solid #4e5058; padding-left: .5rem; margin-bottom: .25rem; }
+.reply a, .attachments a { color: #00a8fc; }
+.content { white-space: pre-wrap; word-wrap: break-word; }
+.attachments { margin: .25rem 0 0; padding-left: 1.25rem; }
+.reactions span { display: inline-block; background: #2b2d31; border-radius: 8px; padding: 0 .4rem; margin: .25rem .25rem 0 0; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{with .Description}}<p>{{.}}</p>{{end}}
+{{end}}
+{{define "message"}}<div class="message" id="m{{.ID}}">
+{{with .Reply}}<div class="reply">↪ <a href="#m{{.ID}}">{{.Author}}</a>: {{.Excerpt}}</div>
+{{end}}<div><span class="author">{{.Author}}</span><span class="time">{{time .TimeSent}}{{if .EditedTime}} (edited){{end}}</span></div>
+{{with .Content}}<div class="content">{{.}}</div>
+{{end}}{{with .Attachments}}<ul class="attachments">{{range .}}<li><a href="{{.Location}}">{{name .}}</a></li>{{end}}</ul>
+{{end}}{{with .Reactions}}<div class="reactions">{{range .}}<span>{{.Emoji}} {{.Count}}</span>{{end}}</div>
+{{end}}</div>
+{{end}}
+{{define "end"}}</body>
+</html>
+{{end}}`))
+
+func (f *htmlPage) begin(channel *valour.Channel, _ bool) error {
+	return htmlTemplate.ExecuteTemplate(f.w, "begin", channel)
+}
+
+func (f *htmlPage) message(e *entry) error {
+	return htmlTemplate.ExecuteTemplate(f.w, "message", e)
+}
+
+func (f *htmlPage) end() error {
+	return htmlTemplate.ExecuteTemplate(f.w, "end", nil)
+}
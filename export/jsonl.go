@@ -0,0 +1,50 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// jsonLines writes each message as a JSON object on its own line
+type jsonLines struct {
+	w   io.Writer
+	enc *json.Encoder
+}
+
+type jsonReaction struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+}
+
+type jsonMessage struct {
+	valour.Message
+
+	AuthorName     string         `json:"authorName"`
+	ReactionCounts []jsonReaction `json:"reactionCounts,omitempty"`
+}
+
+func (f *jsonLines) begin(_ *valour.Channel, _ bool) error {
+	f.enc = json.NewEncoder(f.w)
+	f.enc.SetEscapeHTML(false)
+
+	return nil
+}
+
+func (f *jsonLines) message(e *entry) error {
+	m := jsonMessage{
+		Message:    e.Message,
+		AuthorName: e.Author,
+	}
+
+	for _, r := range e.Reactions {
+		m.ReactionCounts = append(m.ReactionCounts, jsonReaction(r))
+	}
+
+	return f.enc.Encode(m)
+}
+
+func (f *jsonLines) end() error {
+	return nil
+}
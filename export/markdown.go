@@ -0,0 +1,101 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/content"
+)
+
+// timeFormat is how message times are shown in readable exports
+const timeFormat = "2006-01-02 15:04 MST"
+
+// markdownURL escapes the characters that would end a link destination early
+var markdownURL = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+// markdown writes a readable transcript, with anchors so replies can link to the original message.
+// Names and other text not written as markdown by its author are escaped.
+type markdown struct {
+	w io.Writer
+}
+
+func (f *markdown) begin(channel *valour.Channel, resumed bool) error {
+	// A resumed export is appended to the previous file, which already has a heading
+	if resumed {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(f.w, "# %s\n\n", content.Escape(channel.Name))
+
+	if err == nil && channel.Description != "" {
+		_, err = fmt.Fprintf(f.w, "%s\n\n", content.Escape(channel.Description))
+	}
+
+	return err
+}
+
+func (f *markdown) message(e *entry) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<a id=\"m%s\"></a>\n", e.ID)
+	fmt.Fprintf(&b, "**%s** · %s", content.Escape(e.Author), formatTime(e.TimeSent))
+
+	if e.EditedTime != nil {
+		b.WriteString(" (edited)")
+	}
+
+	b.WriteString("\n\n")
+
+	if e.Reply != nil {
+		fmt.Fprintf(&b, "> ↪ [%s](#m%s): %s\n\n", content.Escape(e.Reply.Author), e.Reply.ID, content.Escape(e.Reply.Excerpt))
+	}
+
+	if e.Content != "" {
+		b.WriteString(e.Content)
+		b.WriteString("\n\n")
+	}
+
+	for _, a := range e.Attachments {
+		fmt.Fprintf(&b, "- 📎 [%s](%s)\n", content.Escape(attachmentName(a)), markdownURL.Replace(a.Location))
+	}
+
+	if len(e.Attachments) > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(e.Reactions) > 0 {
+		reactions := make([]string, len(e.Reactions))
+
+		for i, r := range e.Reactions {
+			reactions[i] = fmt.Sprintf("%s %d", r.Emoji, r.Count)
+		}
+
+		fmt.Fprintf(&b, "Reactions: %s\n\n", strings.Join(reactions, " · "))
+	}
+
+	b.WriteString("---\n\n")
+
+	_, err := io.WriteString(f.w, b.String())
+
+	return err
+}
+
+func (f *markdown) end() error {
+	return nil
+}
+
+func attachmentName(a valour.MessageAttachment) string {
+	if a.FileName != "" {
+		return a.FileName
+	}
+
+	return a.Location
+}
+
+// formatTime formats a message time for readable exports
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
@@ -0,0 +1,161 @@
+package valour
+
+import (
+	"net/http"
+	"net/url"
+)
+
+type Friends interface {
+	Friends() ([]User, error)
+	FriendRequests() (*FriendRequests, error)
+	SendFriendRequest(nameAndTag string) (*UserFriend, error)
+	AcceptFriendRequest(userID UserID) (*UserFriend, error)
+	DeclineFriendRequest(userID UserID) error
+	CancelFriendRequest(userID UserID) error
+	RemoveFriend(userID UserID) error
+}
+
+// UserFriend is one side of a friendship, created when UserID adds FriendID.
+// Two users are friends once both have added each other.
+type UserFriend struct {
+	ID       UserFriendID `json:"id"`
+	UserID   UserID       `json:"userId"`
+	FriendID UserID       `json:"friendId"`
+}
+
+// FriendRequests contains the pending friend requests of the account
+type FriendRequests struct {
+	// Incoming are users who have added us, but we haven't added back
+	Incoming []User
+
+	// Outgoing are users we have added, who haven't added us back
+	Outgoing []User
+}
+
+// friendData is the raw response of the friend data endpoint
+type friendData struct {
+	Added   []User `json:"added"`
+	AddedBy []User `json:"addedBy"`
+}
+
+// Friends retrieves the users we are friends with
+// This request always goes to the primary node
+func (n *Node) Friends() ([]User, error) {
+	if !n.IsPrimary() {
+		return n.Primary.Friends()
+	}
+
+	var friends []User
+
+	if err := n.requestJSON(http.MethodGet, apiUserBase+"/me/friends", nil, &friends); err != nil {
+		return nil, err
+	}
+
+	return friends, nil
+}
+
+// FriendRequests retrieves the incoming and outgoing friend requests
+// This request always goes to the primary node
+func (n *Node) FriendRequests() (*FriendRequests, error) {
+	if !n.IsPrimary() {
+		return n.Primary.FriendRequests()
+	}
+
+	var data friendData
+
+	if err := n.requestJSON(http.MethodGet, apiUserBase+"/me/frienddata", nil, &data); err != nil {
+		return nil, err
+	}
+
+	added := make(map[UserID]struct{}, len(data.Added))
+
+	for _, user := range data.Added {
+		added[user.ID] = struct{}{}
+	}
+
+	addedBy := make(map[UserID]struct{}, len(data.AddedBy))
+
+	for _, user := range data.AddedBy {
+		addedBy[user.ID] = struct{}{}
+	}
+
+	var requests FriendRequests
+
+	for _, user := range data.AddedBy {
+		if _, ok := added[user.ID]; !ok {
+			requests.Incoming = append(requests.Incoming, user)
+		}
+	}
+
+	for _, user := range data.Added {
+		if _, ok := addedBy[user.ID]; !ok {
+			requests.Outgoing = append(requests.Outgoing, user)
+		}
+	}
+
+	return &requests, nil
+}
+
+// SendFriendRequest adds a user by their name and tag, such as "name#0000".
+// If the user has already added us, this accepts their request.
+// This request always goes to the primary node
+func (n *Node) SendFriendRequest(nameAndTag string) (*UserFriend, error) {
+	if !n.IsPrimary() {
+		return n.Primary.SendFriendRequest(nameAndTag)
+	}
+
+	var friend UserFriend
+
+	if err := n.requestJSON(http.MethodPost, friendRoute("add", nameAndTag), nil, &friend); err != nil {
+		return nil, err
+	}
+
+	return &friend, nil
+}
+
+// AcceptFriendRequest accepts a user's incoming friend request by adding them back
+func (n *Node) AcceptFriendRequest(userID UserID) (*UserFriend, error) {
+	user, err := n.User(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return n.SendFriendRequest(user.NameAndTag)
+}
+
+// DeclineFriendRequest declines a user's incoming friend request
+func (n *Node) DeclineFriendRequest(userID UserID) error {
+	return n.friendAction("decline", userID)
+}
+
+// CancelFriendRequest cancels a friend request we sent
+func (n *Node) CancelFriendRequest(userID UserID) error {
+	return n.friendAction("cancel", userID)
+}
+
+// RemoveFriend removes a user from our friends.
+// Their side of the friendship remains, turning it back into an incoming request.
+func (n *Node) RemoveFriend(userID UserID) error {
+	return n.friendAction("remove", userID)
+}
+
+// friendAction resolves a user's name and tag, which the friend endpoints are keyed by, and calls the action
+// This request always goes to the primary node
+func (n *Node) friendAction(action string, userID UserID) error {
+	if !n.IsPrimary() {
+		return n.Primary.friendAction(action, userID)
+	}
+
+	user, err := n.User(userID)
+
+	if err != nil {
+		return err
+	}
+
+	return n.requestNoContent(http.MethodPost, friendRoute(action, user.NameAndTag), nil)
+}
+
+func friendRoute(action, nameAndTag string) string {
+	return apiFriendBase + "/" + action + "/" + url.PathEscape(nameAndTag)
+}
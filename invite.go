@@ -0,0 +1,122 @@
+package valour
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PlanetInvite is a code allowing users to join a planet
+type PlanetInvite struct {
+	ID          Snowflake  `json:"id"`
+	Code        string     `json:"code"`
+	PlanetID    PlanetID   `json:"planetId"`
+	IssuerID    UserID     `json:"issuerId"`
+	TimeCreated time.Time  `json:"timeCreated"`
+	TimeExpires *time.Time `json:"timeExpires"`
+}
+
+// Expired checks whether the invite can no longer be used
+func (i PlanetInvite) Expired() bool {
+	return i.TimeExpires != nil && time.Now().After(*i.TimeExpires)
+}
+
+// InviteInfo is an invite resolved without joining its planet
+type InviteInfo struct {
+	PlanetInvite
+	PlanetName string
+}
+
+type Invites interface {
+	CreateInvite(planetID PlanetID, expires *time.Time) (*PlanetInvite, error)
+	PlanetInvites(planetID PlanetID) ([]PlanetInvite, error)
+	DeleteInvite(planetID PlanetID, code string) error
+	ResolveInvite(code string) (*InviteInfo, error)
+}
+
+// CreateInvite creates an invite to a planet, expiring at the specified time or never if nil.
+// Valour invites can be used any number of times, a single use invite can be made with a short
+// expiry and deleted with DeleteInvite once it has been used.
+func (n *Node) CreateInvite(planetID PlanetID, expires *time.Time) (*PlanetInvite, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := n.Me()
+
+	if err != nil {
+		return nil, err
+	}
+
+	invite := PlanetInvite{
+		PlanetID:    planetID,
+		IssuerID:    me.ID,
+		TimeCreated: time.Now().UTC(),
+		TimeExpires: expires,
+	}
+
+	var created PlanetInvite
+
+	if err := node.requestJSON(http.MethodPost, apiInviteBase, invite, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// PlanetInvites retrieves all invites of a planet
+func (n *Node) PlanetInvites(planetID PlanetID) ([]PlanetInvite, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var invites []PlanetInvite
+
+	if err := node.requestJSON(http.MethodGet, planetID.Route("invites"), nil, &invites); err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// DeleteInvite deletes an invite, so it can no longer be used
+func (n *Node) DeleteInvite(planetID PlanetID, code string) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, apiInviteBase+"/"+url.PathEscape(code), nil)
+}
+
+// ResolveInvite retrieves an invite and the name of its planet, without joining it.
+// This request always goes to the primary node
+func (n *Node) ResolveInvite(code string) (*InviteInfo, error) {
+	if !n.IsPrimary() {
+		return n.Primary.ResolveInvite(code)
+	}
+
+	uri := apiInviteBase + "/" + url.PathEscape(code)
+
+	var info InviteInfo
+
+	if err := n.requestJSON(http.MethodGet, uri, nil, &info.PlanetInvite); err != nil {
+		return nil, err
+	}
+
+	name, err := n.requestBytes(http.MethodGet, uri+"/planetname", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info.PlanetName = strings.Trim(strings.TrimSpace(string(name)), `"`)
+
+	return &info, nil
+}
@@ -1,6 +1,58 @@
 package valour
 
-import "net/http"
+import (
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// maxMemberPageSize is the largest page of members the API will return
+const maxMemberPageSize = 100
+
+type Members interface {
+	MyMember(planetID PlanetID) (*Member, error)
+	Member(id MemberID) (*Member, error)
+	MemberByUser(planetID PlanetID, id UserID) (*Member, error)
+	PlanetMembersPage(planetID PlanetID, skip, take int) (*PagedResponse[Member], error)
+	PlanetMembers(planetID PlanetID) ([]Member, error)
+	PlanetMembersIter(planetID PlanetID) iter.Seq2[Member, error]
+	KickMember(planetID PlanetID, id MemberID) error
+	SetMemberNickname(planetID PlanetID, id MemberID, nickname string) (*Member, error)
+	AddMemberRole(planetID PlanetID, id MemberID, roleID RoleID) error
+	RemoveMemberRole(planetID PlanetID, id MemberID, roleID RoleID) error
+}
+
+// DisplayName returns the member's nickname, or their user's name if they don't have one
+func (m Member) DisplayName() string {
+	if m.Nickname != nil && *m.Nickname != "" {
+		return *m.Nickname
+	}
+
+	return m.User.Name
+}
+
+// HasRole checks whether the member holds a role
+func (m Member) HasRole(role Role) bool {
+	return m.RoleMembership.Has(role.FlagBitIndex)
+}
+
+// Roles returns the roles the member holds out of a planet's roles, ordered by position
+func (m Member) Roles(roles []Role) []Role {
+	return m.RoleMembership.Roles(roles)
+}
+
+// RoleIDs returns the IDs of the roles the member holds out of a planet's roles, ordered by position
+func (m Member) RoleIDs(roles []Role) []RoleID {
+	held := m.Roles(roles)
+	ids := make([]RoleID, len(held))
+
+	for i, role := range held {
+		ids[i] = role.ID
+	}
+
+	return ids
+}
 
 func (n *Node) MyMember(planetID PlanetID) (*Member, error) {
 	me, err := n.Me()
@@ -21,7 +73,7 @@ func (n *Node) MyMember(planetID PlanetID) (*Member, error) {
 func (n *Node) Member(id MemberID) (*Member, error) {
 	var member Member
 
-	if err := n.requestJSON(http.MethodGet, "api/members/"+id.String(), nil, &member); err != nil {
+	if err := n.requestJSON(http.MethodGet, id.Route(), nil, &member); err != nil {
 		return nil, err
 	}
 
@@ -31,9 +83,107 @@ func (n *Node) Member(id MemberID) (*Member, error) {
 func (n *Node) MemberByUser(planetID PlanetID, id UserID) (*Member, error) {
 	var member Member
 
-	if err := n.requestJSON(http.MethodGet, "api/members/byuser/"+planetID.String()+"/"+id.String(), nil, &member); err != nil {
+	if err := n.requestJSON(http.MethodGet, apiMemberBase+"/byuser/"+planetID.String()+"/"+id.String(), nil, &member); err != nil {
 		return nil, err
 	}
 
 	return &member, nil
 }
+
+// PlanetMembersPage retrieves a single page of a planet's members
+func (n *Node) PlanetMembersPage(planetID PlanetID, skip, take int) (*PagedResponse[Member], error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	take = clampPageSize(take, maxMemberPageSize)
+
+	q := make(url.Values)
+	q.Set("skip", strconv.Itoa(skip))
+	q.Set("take", strconv.Itoa(take))
+
+	var page PagedResponse[Member]
+
+	if err := node.requestJSON(http.MethodGet, planetID.Route("members")+"?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// PlanetMembers retrieves every member of a planet, requesting as many pages as needed
+func (n *Node) PlanetMembers(planetID PlanetID) ([]Member, error) {
+	return collect(n.PlanetMembersIter(planetID))
+}
+
+// PlanetMembersIter iterates over every member of a planet, requesting pages as they're needed.
+// Iteration stops after the first error.
+func (n *Node) PlanetMembersIter(planetID PlanetID) iter.Seq2[Member, error] {
+	return pages(maxMemberPageSize, func(skip, take int) (*PagedResponse[Member], error) {
+		return n.PlanetMembersPage(planetID, skip, take)
+	})
+}
+
+// KickMember removes a member from a planet. Unlike a ban, they may rejoin.
+func (n *Node) KickMember(planetID PlanetID, id MemberID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, id.Route(), nil)
+}
+
+// SetMemberNickname changes a member's nickname, an empty nickname clears it
+func (n *Node) SetMemberNickname(planetID PlanetID, id MemberID, nickname string) (*Member, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := node.Member(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	member.Nickname = nil
+
+	if nickname != "" {
+		member.Nickname = &nickname
+	}
+
+	var updated Member
+
+	if err := node.requestJSON(http.MethodPut, id.Route(), member, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// AddMemberRole gives a role to a member
+func (n *Node) AddMemberRole(planetID PlanetID, id MemberID, roleID RoleID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodPost, id.Route("roles", roleID.String()), nil)
+}
+
+// RemoveMemberRole takes a role away from a member
+func (n *Node) RemoveMemberRole(planetID PlanetID, id MemberID, roleID RoleID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, id.Route("roles", roleID.String()), nil)
+}
@@ -1,12 +1,16 @@
 package valour
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 
@@ -24,20 +28,37 @@ var ErrInvalidCount = errors.New("invalid message count")
 type Messages interface {
 	Messages(planetID PlanetID, channelID ChannelID, limit uint) ([]Message, error)
 	MessagesBefore(planetID PlanetID, channelID ChannelID, index MessageID, limit uint) ([]Message, error)
+	MessagesAfter(planetID PlanetID, channelID ChannelID, index MessageID, limit uint) ([]Message, error)
+	MessagesBeforeIter(planetID PlanetID, channelID ChannelID, index MessageID) iter.Seq2[Message, error]
+	MessagesAfterIter(planetID PlanetID, channelID ChannelID, index MessageID) iter.Seq2[Message, error]
+	MessagesBetween(planetID PlanetID, channelID ChannelID, start, end time.Time) iter.Seq2[Message, error]
 	Message(id MessageID) (*Message, error)
 	EditMessage(id MessageID, m EditMessageData) (*Message, error)
 	DeleteMessage(id MessageID) error
+	DeleteMessages(planetID PlanetID, channelID ChannelID, ids []MessageID) error
+	Purge(ctx context.Context, planetID PlanetID, channelID ChannelID, filter PurgeFilter, limit int, opts ...PurgeOption) (*PurgeSummary, error)
 	SendMessage(planetID PlanetID, channelID ChannelID, content string) (*Message, error)
 	SendMessageComplex(planetID PlanetID, channelID ChannelID, send SendMessageData) (*Message, error)
+	SendMessageChunked(planetID PlanetID, channelID ChannelID, send SendMessageData) ([]Message, error)
 	MessageReactionAdd(id MessageID, emoji string) error
 	MessageReactionRemove(id MessageID, emoji string) error
+	AddReaction(id MessageID, emoji EmojiRef) error
+	RemoveReaction(id MessageID, emoji EmojiRef) error
+	ReactionUsers(id MessageID, emoji EmojiRef) ([]UserID, error)
+	ToggleReaction(id MessageID, emoji EmojiRef) (bool, error)
+	PinMessage(id MessageID) error
+	UnpinMessage(id MessageID) error
+	PinnedMessages(planetID PlanetID, channelID ChannelID) ([]Message, error)
+	SearchMessagesPage(planetID PlanetID, search MessageSearch, skip, take int) (*PagedResponse[Message], error)
+	SearchMessages(planetID PlanetID, search MessageSearch, limit uint) ([]Message, error)
+	SearchMessagesIter(planetID PlanetID, search MessageSearch) iter.Seq2[Message, error]
 }
 
 type Message struct {
 	ID              MessageID           `json:"id"`
 	PlanetID        PlanetID            `json:"planetId"`
 	ChannelID       ChannelID           `json:"channelId"`
-	ReplyToID       *UserID             `json:"replyToId"`
+	ReplyToID       *MessageID          `json:"replyToId"`
 	ReplyTo         *Message            `json:"replyTo"`
 	AuthorID        UserID              `json:"authorUserId"`
 	MemberID        MemberID            `json:"authorMemberId"`
@@ -67,6 +88,10 @@ type SendMessageData struct {
 	Attachments    []*MessageAttachment `json:"attachments,omitempty"`
 	Embed          *Embed               `json:"-"`
 	Fingerprint    string               `json:"fingerprint"`
+
+	// WaitForDelivery waits up to this long for the sent message to arrive in realtime before returning.
+	// The channel must be joined, see JoinAllChannels. ErrNotDelivered is returned if it doesn't arrive in time.
+	WaitForDelivery time.Duration `json:"-"`
 }
 
 // MarshalJSON marshals necessary options of a message send
@@ -142,69 +167,186 @@ func (e EditMessageData) MarshalJSON() ([]byte, error) {
 	})
 }
 
-// Messages retrieves the latest x messages
+// Messages retrieves the latest x messages, oldest first. Direct and group channels use NullPlanetID.
 func (n *Node) Messages(planetID PlanetID, channelID ChannelID, limit uint) ([]Message, error) {
 	return n.MessagesBefore(planetID, channelID, LatestMessageIndex, limit)
 }
 
-// MessagesBefore retrieves messages before a specific message
+// MessagesBefore retrieves up to limit messages before a specific message, oldest first.
+// A limit of 0 retrieves every message.
 func (n *Node) MessagesBefore(planetID PlanetID, channelID ChannelID, index MessageID, limit uint) ([]Message, error) {
-	msgs := make([]Message, 0, limit)
+	msgs, err := collect(limited(n.MessagesBeforeIter(planetID, channelID, index), limit))
+
+	slices.Reverse(msgs)
+
+	return msgs, err
+}
+
+// MessagesAfter retrieves up to limit messages after a specific message, oldest first.
+// A limit of 0 retrieves every message.
+func (n *Node) MessagesAfter(planetID PlanetID, channelID ChannelID, index MessageID, limit uint) ([]Message, error) {
+	return collect(limited(n.MessagesAfterIter(planetID, channelID, index), limit))
+}
+
+// MessagesBeforeIter iterates backwards through a channel's history from a message, newest first.
+// Use LatestMessageIndex to start from the latest message. Iteration stops after the first error.
+func (n *Node) MessagesBeforeIter(planetID PlanetID, channelID ChannelID, index MessageID) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		for {
+			m, err := n.messagesBefore(planetID, channelID, index, maxMessageLimit)
+
+			if err != nil {
+				yield(Message{}, err)
+				return
+			}
+
+			full := len(m) == maxMessageLimit
+
+			// Guard against repeating messages if the server doesn't respect the index
+			m = slices.DeleteFunc(m, func(msg Message) bool {
+				return msg.ID >= index
+			})
+
+			if len(m) == 0 {
+				return
+			}
+
+			slices.SortFunc(m, func(a, b Message) int {
+				return cmp.Compare(b.ID, a.ID)
+			})
 
-	fetch := uint(maxMessageLimit)
+			for _, msg := range m {
+				if !yield(msg, nil) {
+					return
+				}
+			}
 
-	unlimited := limit == 0
+			if !full {
+				return
+			}
 
-	for limit > 0 || unlimited {
-		if limit > 0 {
-			fetch = uint(intMin(maxMessageLimit, int(limit)))
-			limit -= maxMessageLimit
+			index = m[len(m)-1].ID
 		}
+	}
+}
 
-		m, err := n.messagesBefore(planetID, channelID, index, fetch)
+// MessagesAfterIter iterates forwards through a channel's history from a message, oldest first.
+// Iteration stops after the first error, or once the latest message is reached.
+func (n *Node) MessagesAfterIter(planetID PlanetID, channelID ChannelID, index MessageID) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		for {
+			m, err := n.messagesAfter(planetID, channelID, index, maxMessageLimit)
 
-		if err != nil {
-			return msgs, err
+			if err != nil {
+				yield(Message{}, err)
+				return
+			}
+
+			full := len(m) == maxMessageLimit
+
+			// Guard against repeating messages if the server doesn't respect the index
+			m = slices.DeleteFunc(m, func(msg Message) bool {
+				return msg.ID <= index
+			})
+
+			if len(m) == 0 {
+				return
+			}
+
+			slices.SortFunc(m, func(a, b Message) int {
+				return cmp.Compare(a.ID, b.ID)
+			})
+
+			for _, msg := range m {
+				if !yield(msg, nil) {
+					return
+				}
+			}
+
+			if !full {
+				return
+			}
+
+			index = m[len(m)-1].ID
 		}
+	}
+}
 
-		msgs = append(msgs, m...)
+// MessagesBetween iterates over the messages sent from start until end, oldest first.
+// A zero end iterates up to the latest message. Iteration stops after the first error.
+func (n *Node) MessagesBetween(planetID PlanetID, channelID ChannelID, start, end time.Time) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		// Messages are after the index, so start just before the first ID of the start time
+		index := MessageID(SnowflakeFromTime(start))
 
-		if len(m) < maxMessageLimit {
-			break
+		if index > 0 {
+			index--
 		}
 
-		index = m[len(m)-1].ID
-	}
+		for msg, err := range n.MessagesAfterIter(planetID, channelID, index) {
+			if err == nil && !end.IsZero() && !Snowflake(msg.ID).Time().Before(end) {
+				return
+			}
 
-	if len(msgs) == 0 {
-		return nil, nil
+			if !yield(msg, err) {
+				return
+			}
+		}
 	}
-
-	return msgs, nil
 }
 
-// messagesBefore is called to retrieve messages, used with MessagesBefore to append to a slice
+// messagesBefore retrieves a single batch of messages before index
 func (n *Node) messagesBefore(planetID PlanetID, channelID ChannelID, index MessageID, limit uint) ([]Message, error) {
-	switch {
-	case limit == 0:
-		limit = 50
-	case limit > 64:
-		limit = 64
-	}
+	v := make(url.Values)
+	v.Set("index", index.String())
+	v.Set("count", strconv.FormatUint(uint64(clampMessageLimit(limit)), 10))
 
-	var messages []Message
+	return n.messagesQuery(planetID, channelID, v)
+}
 
+// messagesAfter retrieves a single batch of messages after index
+func (n *Node) messagesAfter(planetID PlanetID, channelID ChannelID, index MessageID, limit uint) ([]Message, error) {
 	v := make(url.Values)
-	v.Set("index", index.String())
-	v.Set("count", strconv.FormatUint(uint64(limit), 10))
+	v.Set("after", index.String())
+	v.Set("count", strconv.FormatUint(uint64(clampMessageLimit(limit)), 10))
+
+	return n.messagesQuery(planetID, channelID, v)
+}
 
-	if err := n.requestJSON(http.MethodGet, planetID.Route("channels", channelID.String(), "messages"), nil, &messages); err != nil {
+func (n *Node) messagesQuery(planetID PlanetID, channelID ChannelID, v url.Values) ([]Message, error) {
+	node, err := n.nodeForChannel(planetID)
+
+	if err != nil {
 		return nil, err
 	}
 
+	var messages []Message
+
+	if err := node.requestJSON(http.MethodGet, channelRoute(planetID, channelID, "messages")+"?"+v.Encode(), nil, &messages); err != nil {
+		return nil, err
+	}
+
+	for i := range messages {
+		if err := messages[i].decodeAttachments(); err != nil {
+			return nil, err
+		}
+	}
+
 	return messages, nil
 }
 
+// clampMessageLimit limits a batch to the range the API accepts
+func clampMessageLimit(limit uint) uint {
+	switch {
+	case limit == 0:
+		return 50
+	case limit > maxMessageLimit:
+		return maxMessageLimit
+	}
+
+	return limit
+}
+
 // Message retrieves a single message
 func (n *Node) Message(id MessageID) (*Message, error) {
 	var message Message
@@ -239,19 +381,7 @@ func (n *Node) EditMessage(id MessageID, m EditMessageData) (*Message, error) {
 
 // DeleteMessage deletes a message
 func (n *Node) DeleteMessage(id MessageID) error {
-	res, err := n.request(http.MethodDelete, id.Route(), nil)
-
-	if err != nil {
-		return err
-	}
-
-	defer res.Body.Close()
-
-	if res.StatusCode == http.StatusOK {
-		return nil
-	}
-
-	return fmt.Errorf("unknown status %d", res.StatusCode)
+	return n.requestNoContent(http.MethodDelete, id.Route(), nil)
 }
 
 // SendMessage sends a simple text message
@@ -261,7 +391,47 @@ func (n *Node) SendMessage(planetID PlanetID, channelID ChannelID, content strin
 	})
 }
 
-// SendMessageComplex sends a message with optional text, attachments, and embeds
+// SendMessageChunked sends content longer than MaxMessageLength as several messages, split with SplitContent.
+// The first message replies to send.ReplyToID, and attachments and embeds are sent with the last message.
+// The messages sent before any error are returned.
+func (n *Node) SendMessageChunked(planetID PlanetID, channelID ChannelID, send SendMessageData) ([]Message, error) {
+	chunks := SplitContent(send.Content, MaxMessageLength)
+
+	messages := make([]Message, 0, len(chunks))
+
+	for i, chunk := range chunks {
+		data := SendMessageData{
+			AuthorMemberID:  send.AuthorMemberID,
+			Content:         chunk,
+			WaitForDelivery: send.WaitForDelivery,
+		}
+
+		if i == 0 {
+			data.ReplyToID = send.ReplyToID
+		}
+
+		if i == len(chunks)-1 {
+			data.Attachments = send.Attachments
+			data.Embed = send.Embed
+		}
+
+		m, err := n.SendMessageComplex(planetID, channelID, data)
+
+		if err != nil {
+			return messages, err
+		}
+
+		// Later chunks reuse our member rather than looking it up again
+		send.AuthorMemberID = m.MemberID
+
+		messages = append(messages, *m)
+	}
+
+	return messages, nil
+}
+
+// SendMessageComplex sends a message with optional text, attachments, and embeds.
+// Messages to direct and group channels use NullPlanetID.
 func (n *Node) SendMessageComplex(planetID PlanetID, channelID ChannelID, send SendMessageData) (*Message, error) {
 	send.PlanetID = planetID
 	send.ChannelID = channelID
@@ -276,7 +446,8 @@ func (n *Node) SendMessageComplex(planetID PlanetID, channelID ChannelID, send S
 	send.Fingerprint = u.String()
 
 	// Until this PR is merged, this is required: https://github.com/Valour-Software/Valour/pull/1426
-	if send.AuthorMemberID == 0 {
+	// Direct and group channels don't belong to a planet, so there is no member to send as.
+	if send.AuthorMemberID == 0 && planetID.IsValid() {
 		myMember, err := n.MyMember(planetID)
 
 		if err != nil {
@@ -286,15 +457,21 @@ func (n *Node) SendMessageComplex(planetID PlanetID, channelID ChannelID, send S
 		send.AuthorMemberID = myMember.ID
 	}
 
+	// Track the fingerprint before sending, as the echo can arrive before the response
+	echo := n.echoes().track(send.Fingerprint)
+
 	res, err := n.request(http.MethodPost, apiMessageBase, send)
 
 	if err != nil {
+		n.echoes().forget(send.Fingerprint)
 		return nil, err
 	}
 
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
+		n.echoes().forget(send.Fingerprint)
+
 		b, _ := io.ReadAll(res.Body)
 		return nil, fmt.Errorf("unknown status %d %s", res.StatusCode, string(b))
 	}
@@ -309,6 +486,15 @@ func (n *Node) SendMessageComplex(planetID PlanetID, channelID ChannelID, send S
 		return nil, err
 	}
 
+	if send.WaitForDelivery > 0 {
+		select {
+		case <-echo:
+		case <-time.After(send.WaitForDelivery):
+			n.echoes().forget(send.Fingerprint)
+			return &m, ErrNotDelivered
+		}
+	}
+
 	return &m, nil
 }
 
@@ -349,12 +535,3 @@ func (n *Node) MessageReactionRemove(id MessageID, emoji string) error {
 
 	return nil
 }
-
-// intMin is a simple math.Min for integers
-func intMin(a, b int) int {
-	if a < b {
-		return a
-	}
-
-	return b
-}
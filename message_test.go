@@ -0,0 +1,187 @@
+package valour
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// historyHandler serves a channel's messages, supporting the index, after and count queries
+func historyHandler(t *testing.T, messages []Message, afterQueries *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		count, _ := strconv.Atoi(q.Get("count"))
+
+		var page []Message
+
+		if after := q.Get("after"); after != "" {
+			afterQueries.Add(1)
+
+			index, err := ParseSnowflake[MessageID](after)
+
+			if err != nil {
+				t.Errorf("invalid after %q", after)
+			}
+
+			for _, m := range messages {
+				if m.ID > index && len(page) < count {
+					page = append(page, m)
+				}
+			}
+		} else {
+			index, err := ParseSnowflake[MessageID](q.Get("index"))
+
+			if err != nil {
+				t.Errorf("invalid index %q", q.Get("index"))
+			}
+
+			for _, m := range slices.Backward(messages) {
+				if m.ID < index && len(page) < count {
+					page = append(page, m)
+				}
+			}
+		}
+
+		writeJSON(w, page)
+	}
+}
+
+func testHistory(n int) []Message {
+	messages := make([]Message, n)
+
+	for i := range messages {
+		messages[i] = Message{ID: MessageID(1000 + i), PlanetID: 1, ChannelID: 2}
+	}
+
+	return messages
+}
+
+func messageIDs(messages []Message) []MessageID {
+	ids := make([]MessageID, len(messages))
+
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+
+	return ids
+}
+
+func TestMessagesPaging(t *testing.T) {
+	history := testHistory(150)
+
+	var afterQueries atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/planets/1/channels/2/messages", historyHandler(t, history, &afterQueries))
+
+	node := newTestNode(t, mux)
+	want := messageIDs(history)
+
+	after, err := node.MessagesAfter(1, 2, 0, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(messageIDs(after), want) {
+		t.Fatalf("MessagesAfter returned %d messages, want all %d in order", len(after), len(want))
+	}
+
+	if afterQueries.Load() == 0 {
+		t.Fatal("MessagesAfter didn't use the after query")
+	}
+
+	before, err := node.MessagesBefore(1, 2, LatestMessageIndex, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(messageIDs(before), want) {
+		t.Fatalf("MessagesBefore returned %d messages, want all %d oldest first", len(before), len(want))
+	}
+
+	limited, err := node.MessagesAfter(1, 2, 1009, 5)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(messageIDs(limited), want[10:15]) {
+		t.Fatalf("MessagesAfter with a limit = %v, want %v", messageIDs(limited), want[10:15])
+	}
+}
+
+func TestMessagesBetween(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history := make([]Message, 10)
+
+	for i := range history {
+		id := MessageID(SnowflakeFromTime(start.Add(time.Duration(i) * time.Minute)))
+		history[i] = Message{ID: id, PlanetID: 1, ChannelID: 2}
+	}
+
+	var afterQueries atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/planets/1/channels/2/messages", historyHandler(t, history, &afterQueries))
+
+	node := newTestNode(t, mux)
+
+	between, err := collect(node.MessagesBetween(1, 2, start.Add(2*time.Minute), start.Add(5*time.Minute)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := messageIDs(history[2:5]); !slices.Equal(messageIDs(between), want) {
+		t.Fatalf("MessagesBetween = %v, want %v", messageIDs(between), want)
+	}
+}
+
+func TestMessagesIterTerminatesWhenIndexIgnored(t *testing.T) {
+	// A server that ignores the index and after queries, always returning the same full page
+	page := testHistory(maxMessageLimit)
+
+	var requests atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/planets/1/channels/2/messages", func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 10 {
+			t.Error("iteration didn't terminate")
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		writeJSON(w, page)
+	})
+
+	node := newTestNode(t, mux)
+
+	after, err := node.MessagesAfter(1, 2, 0, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(messageIDs(after), messageIDs(page)) {
+		t.Fatalf("MessagesAfter returned %d messages, want the %d unique messages", len(after), len(page))
+	}
+
+	requests.Store(0)
+
+	before, err := node.MessagesBefore(1, 2, LatestMessageIndex, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.IsSortedFunc(before, func(a, b Message) int { return cmp.Compare(a.ID, b.ID) }) || len(before) != len(page) {
+		t.Fatalf("MessagesBefore returned %d messages, want the %d unique messages", len(before), len(page))
+	}
+}
@@ -12,7 +12,7 @@ type User struct {
 	Disabled          bool        `json:"disabled"`
 	ValourStaff       bool        `json:"valourStaff"`
 	Status            *string     `json:"status"`
-	UserStateCode     int         `json:"userStateCode"`
+	UserStateCode     UserState   `json:"userStateCode"`
 	TimeLastActive    time.Time   `json:"timeLastActive"`
 	IsMobile          bool        `json:"isMobile"`
 	Compliance        bool        `json:"compliance"`
@@ -27,12 +27,13 @@ type User struct {
 }
 
 type Member struct {
-	ID       MemberID `json:"id"`
-	User     User     `json:"user"`
-	UserID   UserID   `json:"userId"`
-	PlanetID PlanetID `json:"planetId"`
-	Nickname *string  `json:"nickname"`
-	Avatar   *string  `json:"memberAvatar"`
+	ID             MemberID       `json:"id"`
+	User           User           `json:"user"`
+	UserID         UserID         `json:"userId"`
+	PlanetID       PlanetID       `json:"planetId"`
+	Nickname       *string        `json:"nickname"`
+	Avatar         *string        `json:"memberAvatar"`
+	RoleMembership RoleMembership `json:"roleMembership"`
 }
 
 type Reaction struct {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"sync/atomic"
 
 	"github.com/auroradevllc/apiclient"
 	"github.com/auroradevllc/apiclient/multipart"
@@ -31,6 +32,8 @@ type Node struct {
 	members        cmap.ConcurrentMap[PlanetID, Member]
 	planetNodeList cmap.ConcurrentMap[PlanetID, string]
 	childNodes     cmap.ConcurrentMap[string, *Node]
+	echoTracker    *echoTracker
+	noBulkDelete   atomic.Bool
 
 	Name    string
 	Primary *Node
@@ -56,6 +59,7 @@ func NewNode(baseAddress, name, token string, opts ...NodeOption) (*Node, error)
 		token:          token,
 		baseAddress:    baseAddress,
 		planetNodeList: cmap.NewStringer[PlanetID, string](),
+		echoTracker:    newEchoTracker(),
 		Name:           name,
 	}
 
@@ -204,9 +208,40 @@ func (n *Node) JoinAllChannels(ctx context.Context) error {
 		})
 	}
 
+	// Direct and group channels don't belong to a planet, their events come from the primary node
+	// Failing to list them shouldn't prevent joining planet channels
+	directChannels, err := n.DirectChannels()
+
+	if err != nil {
+		log.WithError(err).Warn("Failed to get direct channels")
+	}
+
+	if len(directChannels) > 0 && !n.Connected() {
+		if err := n.Open(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, channel := range directChannels {
+		wg.Go(func(ctx context.Context) error {
+			log.WithField("channel", channel.ID).Debug("Joining direct channel")
+
+			return n.rtc.JoinChannel(channel.ID)
+		})
+	}
+
 	return wg.Wait()
 }
 
+// primaryNode returns the primary node, which holds the state shared by all nodes
+func (n *Node) primaryNode() *Node {
+	if n.IsPrimary() {
+		return n
+	}
+
+	return n.Primary
+}
+
 // IsPrimary checks whether a node is the primary Valour node
 func (n *Node) IsPrimary() bool {
 	return n.Primary == nil
@@ -284,6 +319,21 @@ func (n *Node) requestBytes(method, uri string, body any) ([]byte, error) {
 	return res.Bytes()
 }
 
+// requestNoContent sends a request, only checking the response status
+func (n *Node) requestNoContent(method, uri string, body any) error {
+	res, err := n.request(method, uri, body)
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return newStatusError(res)
+	}
+
+	return res.Close()
+}
+
 func (n *Node) requestJSON(method, uri string, body any, dest any) error {
 	res, err := n.request(method, uri, body)
 
@@ -292,8 +342,52 @@ func (n *Node) requestJSON(method, uri string, body any, dest any) error {
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("got status code %d", res.StatusCode)
+		return newStatusError(res)
 	}
 
 	return res.Unmarshal(&dest)
 }
+
+// StatusError is returned when the API responds with an unexpected status code
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func newStatusError(res *apiclient.Response) *StatusError {
+	b, _ := res.Bytes()
+
+	return &StatusError{
+		StatusCode: res.StatusCode,
+		Body:       string(b),
+	}
+}
+
+func (e *StatusError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("got status code %d: %s", e.StatusCode, e.Body)
+	}
+
+	return fmt.Sprintf("got status code %d", e.StatusCode)
+}
+
+// IsNotFound checks whether an error is an API response with status 404
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsRateLimited checks whether an error is an API response with status 429
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
+}
+
+// statusCode returns the status code of an API error response, or 0 for other errors
+func statusCode(err error) int {
+	var statusErr *StatusError
+
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+
+	return 0
+}
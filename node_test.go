@@ -0,0 +1,41 @@
+package valour
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testNodeName is the name every planet resolves to on a test node
+const testNodeName = "test"
+
+// newTestNode creates a primary node backed by mux, which only needs to handle the routes under test.
+// Node name lookups are answered so every planet is served by the returned node.
+func newTestNode(t *testing.T, mux *http.ServeMux) *Node {
+	t.Helper()
+
+	mux.HandleFunc("GET /api/node/name", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testNodeName))
+	})
+	mux.HandleFunc("GET /api/node/planet/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testNodeName))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	node, err := NewNode(server.URL, testNodeName, "token")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+// writeJSON responds with v encoded as JSON
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
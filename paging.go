@@ -0,0 +1,87 @@
+package valour
+
+import "iter"
+
+// PagedResponse is a single page of results from a paginated endpoint
+type PagedResponse[V any] struct {
+	Items      []V `json:"items"`
+	TotalCount int `json:"totalCount"`
+}
+
+// pageFunc requests a single page of results
+type pageFunc[V any] func(skip, take int) (*PagedResponse[V], error)
+
+// clampPageSize limits take to the range the API accepts, defaulting to the maximum
+func clampPageSize(take, max int) int {
+	if take <= 0 || take > max {
+		return max
+	}
+
+	return take
+}
+
+// pages iterates over every item of a paginated endpoint, requesting pages as they're needed.
+// Iteration stops after the first error.
+func pages[V any](pageSize int, fetch pageFunc[V]) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		skip := 0
+
+		for {
+			page, err := fetch(skip, pageSize)
+
+			if err != nil {
+				var zero V
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			skip += len(page.Items)
+
+			if len(page.Items) == 0 || skip >= page.TotalCount {
+				return
+			}
+		}
+	}
+}
+
+// collect gathers all items of an iterator, returning those retrieved before any error
+func collect[V any](seq iter.Seq2[V, error]) ([]V, error) {
+	var items []V
+
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// limited stops an iterator after n items, or never if n is 0
+func limited[V any](seq iter.Seq2[V, error], n uint) iter.Seq2[V, error] {
+	if n == 0 {
+		return seq
+	}
+
+	return func(yield func(V, error) bool) {
+		var count uint
+
+		for item, err := range seq {
+			if !yield(item, err) {
+				return
+			}
+
+			if count++; count >= n {
+				return
+			}
+		}
+	}
+}
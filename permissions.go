@@ -0,0 +1,234 @@
+package valour
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+)
+
+// PermissionState is the value of a single permission in a PermissionsNode
+type PermissionState int
+
+const (
+	// PermissionUndefined leaves the permission to lower priority roles, or the role's base permissions
+	PermissionUndefined PermissionState = iota
+	PermissionAllow
+	PermissionDeny
+)
+
+// AllPermissions has every permission bit set, given to planet owners and administrators
+const AllPermissions = ^uint64(0)
+
+// PermissionsNode overrides a role's permissions in a single channel or category.
+// Bits set in Mask are defined by the node, with Code holding their values.
+type PermissionsNode struct {
+	ID       PermissionsNodeID `json:"id"`
+	PlanetID PlanetID          `json:"planetId"`
+
+	// TargetID is the channel or category the node applies to
+	TargetID ChannelID `json:"targetId"`
+	RoleID   RoleID    `json:"roleId"`
+	Mask     uint64    `json:"mask"`
+	Code     uint64    `json:"code"`
+
+	// TargetType is the type of channel the permissions apply to.
+	// Nodes on a category may target the category itself, or the chat and voice channels inheriting from it.
+	TargetType ChannelType `json:"targetType"`
+}
+
+// State returns whether the node allows, denies, or doesn't define a permission
+func (p PermissionsNode) State(permission uint64) PermissionState {
+	switch {
+	case p.Mask&permission == 0:
+		return PermissionUndefined
+	case p.Code&permission == permission:
+		return PermissionAllow
+	default:
+		return PermissionDeny
+	}
+}
+
+// Set allows, denies, or clears a permission on the node
+func (p *PermissionsNode) Set(permission uint64, state PermissionState) {
+	switch state {
+	case PermissionAllow:
+		p.Mask |= permission
+		p.Code |= permission
+	case PermissionDeny:
+		p.Mask |= permission
+		p.Code &^= permission
+	default:
+		p.Mask &^= permission
+		p.Code &^= permission
+	}
+}
+
+// Apply overrides the permissions defined by the node
+func (p PermissionsNode) Apply(permissions uint64) uint64 {
+	return permissions&^p.Mask | p.Code&p.Mask
+}
+
+type PermissionsNodes interface {
+	PermissionsNode(planetID PlanetID, id PermissionsNodeID) (*PermissionsNode, error)
+	PermissionsNodes(planetID PlanetID) ([]PermissionsNode, error)
+	CreatePermissionsNode(planetID PlanetID, node PermissionsNode) (*PermissionsNode, error)
+	UpdatePermissionsNode(planetID PlanetID, node PermissionsNode) (*PermissionsNode, error)
+	DeletePermissionsNode(planetID PlanetID, id PermissionsNodeID) error
+}
+
+// PermissionsNode retrieves a single permissions node
+func (n *Node) PermissionsNode(planetID PlanetID, id PermissionsNodeID) (*PermissionsNode, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var p PermissionsNode
+
+	if err := node.requestJSON(http.MethodGet, id.Route(), nil, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// PermissionsNodes retrieves all permissions nodes of a planet
+func (n *Node) PermissionsNodes(planetID PlanetID) ([]PermissionsNode, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []PermissionsNode
+
+	if err := node.requestJSON(http.MethodGet, planetID.Route("permissionsnodes"), nil, &nodes); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// CreatePermissionsNode creates a permission override for a role in a channel or category
+func (n *Node) CreatePermissionsNode(planetID PlanetID, p PermissionsNode) (*PermissionsNode, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.PlanetID = planetID
+
+	var created PermissionsNode
+
+	if err := node.requestJSON(http.MethodPost, apiPermissionsNodeBase, p, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdatePermissionsNode replaces the mask and values of an existing permissions node
+func (n *Node) UpdatePermissionsNode(planetID PlanetID, p PermissionsNode) (*PermissionsNode, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.PlanetID = planetID
+
+	var updated PermissionsNode
+
+	if err := node.requestJSON(http.MethodPut, p.ID.Route(), p, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeletePermissionsNode deletes a permissions node, returning the role to its base permissions in the target
+func (n *Node) DeletePermissionsNode(planetID PlanetID, id PermissionsNodeID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodDelete, id.Route(), nil)
+}
+
+// ChannelPermissions calculates a member's permissions in a channel.
+//
+// The member's roles are applied from the lowest to the highest priority. Each role contributes its
+// base permissions for the channel type, overridden by any node for that role on the channel, or on
+// the category the channel inherits its permissions from. Planet owners and administrators have every permission.
+func ChannelPermissions(planet Planet, member Member, channel Channel, channels []Channel, roles []Role, nodes []PermissionsNode) uint64 {
+	if member.UserID == planet.OwnerID {
+		return AllPermissions
+	}
+
+	held := member.Roles(roles)
+
+	for _, role := range held {
+		if role.IsAdmin {
+			return AllPermissions
+		}
+	}
+
+	target := permissionsTarget(channel, channels)
+
+	// Lowest priority (highest position) first, so higher roles override them
+	slices.SortFunc(held, func(a, b Role) int {
+		return cmp.Compare(b.Position, a.Position)
+	})
+
+	var permissions uint64
+
+	for _, role := range held {
+		permissions |= basePermissions(role, channel.ChannelType)
+
+		for _, node := range nodes {
+			if node.RoleID == role.ID && node.TargetID == target.ID && node.TargetType == channel.ChannelType {
+				permissions = node.Apply(permissions)
+			}
+		}
+	}
+
+	return permissions
+}
+
+// permissionsTarget follows InheritsPerms up to the channel or category defining a channel's permissions
+func permissionsTarget(channel Channel, channels []Channel) Channel {
+	// Bounded by the channel count, in case of a cycle from inconsistent data
+	for range len(channels) {
+		if !channel.InheritsPerms || !channel.ParentID.IsValid() {
+			break
+		}
+
+		idx := slices.IndexFunc(channels, func(c Channel) bool {
+			return c.ID == channel.ParentID
+		})
+
+		if idx == -1 {
+			break
+		}
+
+		channel = channels[idx]
+	}
+
+	return channel
+}
+
+// basePermissions returns a role's permissions for a type of channel
+func basePermissions(role Role, channelType ChannelType) uint64 {
+	switch channelType {
+	case PlanetCategory:
+		return uint64(role.CategoryPermissions)
+	case PlanetVoice:
+		return uint64(role.VoicePermissions)
+	default:
+		return uint64(role.ChatPermissions)
+	}
+}
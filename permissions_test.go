@@ -0,0 +1,160 @@
+package valour
+
+import "testing"
+
+func TestChannelPermissions(t *testing.T) {
+	const (
+		view uint64 = 1 << iota
+		send
+		manage
+	)
+
+	planet := Planet{ID: 1, OwnerID: 100}
+
+	everyone := Role{ID: 1, Position: 10, FlagBitIndex: 0, ChatPermissions: int(view | send), CategoryPermissions: int(view)}
+	moderator := Role{ID: 2, Position: 5, FlagBitIndex: 64}
+	admin := Role{ID: 3, Position: 1, FlagBitIndex: 255, IsAdmin: true}
+	roles := []Role{admin, everyone, moderator}
+
+	category := Channel{ID: 10, PlanetID: 1, ChannelType: PlanetCategory}
+	private := Channel{ID: 11, PlanetID: 1, ChannelType: PlanetChat, ParentID: 10, InheritsPerms: true}
+	separate := Channel{ID: 12, PlanetID: 1, ChannelType: PlanetChat, ParentID: 10}
+	channels := []Channel{category, private, separate}
+
+	member := func(userID UserID, held ...Role) Member {
+		m := Member{ID: MemberID(userID), UserID: userID, PlanetID: 1}
+
+		for _, role := range held {
+			m.RoleMembership.Set(role.FlagBitIndex, true)
+		}
+
+		return m
+	}
+
+	node := func(role Role, target Channel, targetType ChannelType, allow, deny uint64) PermissionsNode {
+		n := PermissionsNode{RoleID: role.ID, TargetID: target.ID, TargetType: targetType}
+		n.Set(allow, PermissionAllow)
+		n.Set(deny, PermissionDeny)
+
+		return n
+	}
+
+	// Hides the category's chat channels from everyone, then shows them to moderators
+	hidden := []PermissionsNode{
+		node(everyone, category, PlanetChat, 0, view|send),
+		node(moderator, category, PlanetChat, view|send|manage, 0),
+	}
+
+	tests := []struct {
+		name    string
+		member  Member
+		channel Channel
+		nodes   []PermissionsNode
+		want    uint64
+	}{
+		{
+			name:    "base permissions",
+			member:  member(1, everyone),
+			channel: private,
+			want:    view | send,
+		},
+		{
+			name:    "base permissions of the channel type",
+			member:  member(1, everyone),
+			channel: category,
+			want:    view,
+		},
+		{
+			name:    "no roles",
+			member:  member(1),
+			channel: private,
+			want:    0,
+		},
+		{
+			name:    "category deny hides an inheriting channel",
+			member:  member(1, everyone),
+			channel: private,
+			nodes:   hidden,
+			want:    0,
+		},
+		{
+			name:    "category nodes don't apply to channels with their own permissions",
+			member:  member(1, everyone),
+			channel: separate,
+			nodes:   hidden,
+			want:    view | send,
+		},
+		{
+			name:    "higher role overrides a lower role's node",
+			member:  member(1, everyone, moderator),
+			channel: private,
+			nodes:   hidden,
+			want:    view | send | manage,
+		},
+		{
+			name:    "lower role doesn't override a higher role's node",
+			member:  member(1, everyone, moderator),
+			channel: private,
+			nodes: []PermissionsNode{
+				node(everyone, category, PlanetChat, manage, 0),
+				node(moderator, category, PlanetChat, 0, view),
+			},
+			want: send | manage,
+		},
+		{
+			name:    "node for another channel type",
+			member:  member(1, everyone),
+			channel: private,
+			nodes:   []PermissionsNode{node(everyone, category, PlanetCategory, 0, view|send)},
+			want:    view | send,
+		},
+		{
+			name:    "node for the category itself",
+			member:  member(1, everyone),
+			channel: category,
+			nodes:   []PermissionsNode{node(everyone, category, PlanetCategory, 0, view)},
+			want:    0,
+		},
+		{
+			name:    "node for a role the member doesn't hold",
+			member:  member(1, everyone),
+			channel: private,
+			nodes:   []PermissionsNode{node(moderator, category, PlanetChat, manage, 0)},
+			want:    view | send,
+		},
+		{
+			name:    "owner",
+			member:  member(100),
+			channel: private,
+			nodes:   hidden,
+			want:    AllPermissions,
+		},
+		{
+			name:    "administrator",
+			member:  member(1, everyone, admin),
+			channel: private,
+			nodes:   hidden,
+			want:    AllPermissions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChannelPermissions(planet, tt.member, tt.channel, channels, roles, tt.nodes); got != tt.want {
+				t.Fatalf("ChannelPermissions = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionsTargetCycle(t *testing.T) {
+	channels := []Channel{
+		{ID: 1, ParentID: 2, InheritsPerms: true},
+		{ID: 2, ParentID: 1, InheritsPerms: true},
+	}
+
+	// Inconsistent data must not loop forever
+	if target := permissionsTarget(channels[0], channels); target.ID != 1 && target.ID != 2 {
+		t.Fatalf("target = %d, want one of the channels in the cycle", target.ID)
+	}
+}
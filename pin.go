@@ -0,0 +1,36 @@
+package valour
+
+import "net/http"
+
+// PinMessage pins a message to its channel
+func (n *Node) PinMessage(id MessageID) error {
+	return n.requestNoContent(http.MethodPost, id.Route("pin"), nil)
+}
+
+// UnpinMessage removes a message from its channel's pins
+func (n *Node) UnpinMessage(id MessageID) error {
+	return n.requestNoContent(http.MethodDelete, id.Route("pin"), nil)
+}
+
+// PinnedMessages retrieves a channel's pinned messages. Direct and group channels use NullPlanetID.
+func (n *Node) PinnedMessages(planetID PlanetID, channelID ChannelID) ([]Message, error) {
+	node, err := n.nodeForChannel(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+
+	if err := node.requestJSON(http.MethodGet, channelRoute(planetID, channelID, "pins"), nil, &messages); err != nil {
+		return nil, err
+	}
+
+	for i := range messages {
+		if err := messages[i].decodeAttachments(); err != nil {
+			return nil, err
+		}
+	}
+
+	return messages, nil
+}
@@ -2,6 +2,7 @@ package valour
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -17,6 +18,8 @@ type Planets interface {
 	DeletePlanet(id PlanetID) error
 	PlanetInitialData(id PlanetID) (*PlanetInitialData, error)
 	JoinPlanet(planet PlanetID, inviteCode string) error
+	UploadPlanetIcon(id PlanetID, fileName string, r io.Reader, size int64) error
+	UploadPlanetBackground(id PlanetID, fileName string, r io.Reader, size int64) (string, error)
 }
 
 // Planet is Valour's representation of a server/group
@@ -41,6 +44,8 @@ type PlanetInitialData struct {
 	Channels []Channel `json:"channels"`
 	Roles    []Role    `json:"roles"`
 	Emojis   []Emoji   `json:"emojis"`
+
+	PermissionsNodes []PermissionsNode `json:"permissionsNodes"`
 }
 
 func (n *Node) NodeForPlanet(planetID PlanetID) (*Node, error) {
@@ -268,3 +273,33 @@ func (n *Node) JoinPlanet(planet PlanetID, inviteCode string) error {
 
 	return fmt.Errorf("unknown status %d", res.StatusCode)
 }
+
+// UploadPlanetIcon sets a planet's icon to a png, jpeg or gif image
+func (n *Node) UploadPlanetIcon(id PlanetID, fileName string, r io.Reader, size int64) error {
+	node, err := n.NodeForPlanet(id)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = node.uploadImage("upload/planet/"+id.String(), fileName, r, size)
+
+	return err
+}
+
+// UploadPlanetBackground sets a planet's background image, returning its location
+func (n *Node) UploadPlanetBackground(id PlanetID, fileName string, r io.Reader, size int64) (string, error) {
+	node, err := n.NodeForPlanet(id)
+
+	if err != nil {
+		return "", err
+	}
+
+	upload, err := node.uploadImage("upload/planetbg/"+id.String(), fileName, r, size)
+
+	if err != nil {
+		return "", err
+	}
+
+	return upload.Location, nil
+}
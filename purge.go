@@ -0,0 +1,393 @@
+package valour
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// maxBulkDelete is the most messages deleted with one bulk delete request
+	maxBulkDelete = 100
+
+	defaultPurgeConcurrency = 4
+	defaultPurgeScanLimit   = 1000
+
+	// maxRateLimitRetries is how many times a rate limited delete is retried
+	maxRateLimitRetries = 3
+)
+
+// PurgeFilter selects the messages to purge. Every condition that is set must match.
+type PurgeFilter struct {
+	// Authors only matches messages sent by these users
+	Authors []UserID
+
+	// Before only matches messages sent before this time, such as to keep recent messages
+	Before time.Time
+
+	// After only matches messages sent after this time. History older than this isn't scanned.
+	After time.Time
+
+	// Content only matches messages with content matching this expression
+	Content *regexp.Regexp
+
+	// HasAttachments only matches messages with attachments
+	HasAttachments bool
+
+	// Bots only matches messages sent by bots
+	Bots bool
+
+	// Func is an additional condition checked after the others
+	Func func(m Message) bool
+}
+
+// PurgeProgress is the state of a running purge
+type PurgeProgress struct {
+	// Scanned is the number of messages checked against the filter
+	Scanned int
+
+	// Matched is the number of messages the filter selected
+	Matched int
+
+	Deleted int
+	Failed  int
+}
+
+// PurgeSummary is the result of a finished purge
+type PurgeSummary struct {
+	PurgeProgress
+
+	// Errors are the errors of failed deletions
+	Errors []error
+
+	Duration time.Duration
+}
+
+type purgeConfig struct {
+	concurrency int
+	scanLimit   int
+	progress    func(PurgeProgress)
+}
+
+type PurgeOption func(c *purgeConfig)
+
+// WithPurgeConcurrency sets how many delete requests are made at once, defaulting to 4
+func WithPurgeConcurrency(n int) PurgeOption {
+	return func(c *purgeConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithPurgeScanLimit sets how many messages are scanned looking for matches, defaulting to 1000.
+// A limit of 0 scans the whole history, or up to PurgeFilter.After.
+func WithPurgeScanLimit(n int) PurgeOption {
+	return func(c *purgeConfig) {
+		c.scanLimit = n
+	}
+}
+
+// WithPurgeProgress calls fn as the purge scans and deletes messages.
+// Calls are made from one goroutine at a time.
+func WithPurgeProgress(fn func(PurgeProgress)) PurgeOption {
+	return func(c *purgeConfig) {
+		c.progress = fn
+	}
+}
+
+// Purge deletes up to limit of a channel's most recent messages matching filter.
+// Messages are deleted in bulk where the API supports it, otherwise individually with limited concurrency.
+// The summary is returned even when the purge is interrupted by an error or the context.
+func (n *Node) Purge(ctx context.Context, planetID PlanetID, channelID ChannelID, filter PurgeFilter, limit int, opts ...PurgeOption) (*PurgeSummary, error) {
+	cfg := purgeConfig{
+		concurrency: defaultPurgeConcurrency,
+		scanLimit:   defaultPurgeScanLimit,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	p := &purge{
+		node:    n,
+		cfg:     cfg,
+		filter:  filter,
+		started: time.Now(),
+		bots:    make(map[UserID]bool),
+	}
+
+	ids, err := p.scan(ctx, planetID, channelID, limit)
+
+	if err == nil {
+		err = p.delete(ctx, planetID, channelID, ids)
+	}
+
+	return p.summary(), err
+}
+
+// purge is the state of a single Purge call
+type purge struct {
+	node    *Node
+	cfg     purgeConfig
+	filter  PurgeFilter
+	started time.Time
+
+	// bots caches whether authors are bots
+	bots map[UserID]bool
+
+	mu       sync.Mutex
+	progress PurgeProgress
+	errors   []error
+}
+
+// scan walks the channel history from the latest message, collecting the messages to delete
+func (p *purge) scan(ctx context.Context, planetID PlanetID, channelID ChannelID, limit int) ([]MessageID, error) {
+	var ids []MessageID
+
+	scanned := 0
+
+	for m, err := range p.node.MessagesBeforeIter(planetID, channelID, LatestMessageIndex) {
+		if err != nil {
+			return ids, err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return ids, err
+		}
+
+		// History is newest first, so nothing further can match
+		if !p.filter.After.IsZero() && !m.TimeSent.After(p.filter.After) {
+			break
+		}
+
+		matched, err := p.matches(m)
+
+		if err != nil {
+			return ids, err
+		}
+
+		if matched {
+			ids = append(ids, m.ID)
+		}
+
+		scanned++
+
+		p.update(func(pr *PurgeProgress) {
+			pr.Scanned++
+
+			if matched {
+				pr.Matched++
+			}
+		})
+
+		if (limit > 0 && len(ids) >= limit) || (p.cfg.scanLimit > 0 && scanned >= p.cfg.scanLimit) {
+			break
+		}
+	}
+
+	return ids, nil
+}
+
+func (p *purge) matches(m Message) (bool, error) {
+	f := p.filter
+
+	if len(f.Authors) > 0 && !slices.Contains(f.Authors, m.AuthorID) {
+		return false, nil
+	}
+
+	if !f.Before.IsZero() && !m.TimeSent.Before(f.Before) {
+		return false, nil
+	}
+
+	if f.Content != nil && !f.Content.MatchString(m.Content) {
+		return false, nil
+	}
+
+	if f.HasAttachments && len(m.Attachments) == 0 {
+		return false, nil
+	}
+
+	if f.Bots {
+		bot, ok := p.bots[m.AuthorID]
+
+		if !ok {
+			user, err := p.node.User(m.AuthorID)
+
+			if err != nil {
+				return false, err
+			}
+
+			bot = user.Bot
+			p.bots[m.AuthorID] = bot
+		}
+
+		if !bot {
+			return false, nil
+		}
+	}
+
+	if f.Func != nil && !f.Func(m) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// delete removes the matched messages, in bulk if possible
+func (p *purge) delete(ctx context.Context, planetID PlanetID, channelID ChannelID, ids []MessageID) error {
+	for batch := range slices.Chunk(ids, maxBulkDelete) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		supported, err := p.node.bulkDelete(planetID, channelID, batch)
+
+		if supported {
+			if err != nil {
+				p.fail(len(batch), err)
+			} else {
+				p.update(func(pr *PurgeProgress) {
+					pr.Deleted += len(batch)
+				})
+			}
+
+			continue
+		}
+
+		if err := p.deleteEach(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteEach deletes messages one by one, with limited concurrency
+func (p *purge) deleteEach(ctx context.Context, ids []MessageID) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(p.cfg.concurrency, 1))
+
+	for _, id := range ids {
+		g.Go(func() error {
+			err := retryRateLimited(ctx, func() error {
+				return p.node.DeleteMessage(id)
+			})
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err != nil {
+				p.fail(1, err)
+			} else {
+				p.update(func(pr *PurgeProgress) {
+					pr.Deleted++
+				})
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+func (p *purge) fail(count int, err error) {
+	p.update(func(pr *PurgeProgress) {
+		pr.Failed += count
+		p.errors = append(p.errors, err)
+	})
+}
+
+// update changes the progress and reports it
+func (p *purge) update(fn func(pr *PurgeProgress)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fn(&p.progress)
+
+	if p.cfg.progress != nil {
+		p.cfg.progress(p.progress)
+	}
+}
+
+func (p *purge) summary() *PurgeSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return &PurgeSummary{
+		PurgeProgress: p.progress,
+		Errors:        p.errors,
+		Duration:      time.Since(p.started),
+	}
+}
+
+// retryRateLimited calls fn, waiting and retrying when it's rate limited
+func retryRateLimited(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+
+		if !IsRateLimited(err) || attempt > maxRateLimitRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// DeleteMessages deletes several messages of a channel at once.
+// If the node doesn't support bulk deletion, the messages are deleted one at a time.
+func (n *Node) DeleteMessages(planetID PlanetID, channelID ChannelID, ids []MessageID) error {
+	if supported, err := n.bulkDelete(planetID, channelID, ids); supported {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := n.DeleteMessage(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bulkDelete deletes messages with a single request, returning false if the node has no bulk delete endpoint
+func (n *Node) bulkDelete(planetID PlanetID, channelID ChannelID, ids []MessageID) (bool, error) {
+	primary := n.primaryNode()
+
+	if primary.noBulkDelete.Load() {
+		return false, nil
+	}
+
+	node, err := n.nodeForChannel(planetID)
+
+	if err != nil {
+		return true, err
+	}
+
+	err = node.requestNoContent(http.MethodPost, channelRoute(planetID, channelID, "messages", "bulkdelete"), ids)
+
+	switch statusCode(err) {
+	case http.StatusMethodNotAllowed:
+	case http.StatusNotFound:
+		// A missing channel is also not found, which doesn't mean the endpoint is missing
+		if _, chErr := node.Channel(planetID, channelID); chErr != nil {
+			return true, err
+		}
+	default:
+		return true, err
+	}
+
+	// Remember the endpoint is missing, so later calls go straight to deleting individually
+	primary.noBulkDelete.Store(true)
+
+	return false, nil
+}
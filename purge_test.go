@@ -0,0 +1,324 @@
+package valour
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// purgeServer serves a channel history, recording bulk and individual deletions
+type purgeServer struct {
+	t   *testing.T
+	mux *http.ServeMux
+
+	mu      sync.Mutex
+	bulk    [][]MessageID
+	deleted []MessageID
+}
+
+func newPurgeServer(t *testing.T, history []Message) *purgeServer {
+	s := &purgeServer{t: t, mux: http.NewServeMux()}
+
+	var afterQueries atomic.Int32
+
+	s.mux.HandleFunc("GET /api/planets/1/channels/2/messages", historyHandler(t, history, &afterQueries))
+
+	return s
+}
+
+// handleBulk serves the bulk delete endpoint, unless status is set to respond with instead
+func (s *purgeServer) handleBulk(status int) {
+	s.mux.HandleFunc("POST /api/planets/1/channels/2/messages/bulkdelete", func(w http.ResponseWriter, r *http.Request) {
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+
+		var ids []MessageID
+
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			s.t.Errorf("invalid bulk delete body: %v", err)
+		}
+
+		s.mu.Lock()
+		s.bulk = append(s.bulk, ids)
+		s.mu.Unlock()
+	})
+}
+
+// handleDelete serves individual deletions, rate limiting each message the first limited times it's deleted
+func (s *purgeServer) handleDelete(limited int) {
+	attempts := make(map[MessageID]int)
+
+	s.mux.HandleFunc("DELETE /api/messages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseSnowflake[MessageID](r.PathValue("id"))
+
+		if err != nil {
+			s.t.Errorf("invalid message id %q", r.PathValue("id"))
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		attempts[id]++
+
+		if attempts[id] <= limited {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		s.deleted = append(s.deleted, id)
+	})
+}
+
+func (s *purgeServer) bulkDeleted() [][]MessageID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.bulk)
+}
+
+func (s *purgeServer) individuallyDeleted() []MessageID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := slices.Clone(s.deleted)
+	slices.Sort(deleted)
+
+	return deleted
+}
+
+func TestPurgeFilter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	msg := func(id MessageID, author UserID, content string, minutes int) Message {
+		return Message{ID: id, PlanetID: 1, ChannelID: 2, AuthorID: author, Content: content, TimeSent: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	withAttachment := msg(106, 10, "spam with file", 6)
+	withAttachment.Attachments = []MessageAttachment{{Location: "https://cdn.valour.gg/a.png"}}
+
+	history := []Message{
+		msg(100, 10, "spam too old", 0),
+		msg(101, 10, "spam", 1),
+		msg(102, 11, "spam from a person", 2),
+		msg(103, 10, "hello", 3),
+		msg(104, 12, "spam from another bot", 4),
+		msg(105, 10, "spam keep", 5),
+		withAttachment,
+		msg(107, 10, "spam too new", 30),
+	}
+
+	s := newPurgeServer(t, history)
+	s.handleBulk(0)
+
+	var userLookups atomic.Int32
+
+	s.mux.HandleFunc("GET /api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		userLookups.Add(1)
+
+		id, _ := ParseSnowflake[UserID](r.PathValue("id"))
+
+		writeJSON(w, User{ID: id, Bot: id != 11})
+	})
+
+	node := newTestNode(t, s.mux)
+
+	filter := PurgeFilter{
+		Authors: []UserID{10, 11},
+		After:   start,
+		Before:  start.Add(10 * time.Minute),
+		Content: regexp.MustCompile(`^spam`),
+		Bots:    true,
+		Func: func(m Message) bool {
+			return m.Content != "spam keep"
+		},
+	}
+
+	summary, err := node.Purge(context.Background(), 1, 2, filter, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages 106 and 101 match, while 100 stops the scan by being sent at the After time
+	want := [][]MessageID{{106, 101}}
+
+	if got := s.bulkDeleted(); len(got) != 1 || !slices.Equal(got[0], want[0]) {
+		t.Fatalf("bulk deleted %v, want %v", got, want)
+	}
+
+	if summary.Scanned != 7 || summary.Matched != 2 || summary.Deleted != 2 || summary.Failed != 0 {
+		t.Fatalf("summary = %+v, want 7 scanned with 2 matched and deleted", summary.PurgeProgress)
+	}
+
+	// Whether an author is a bot is looked up once per author reaching the check
+	if lookups := userLookups.Load(); lookups != 2 {
+		t.Fatalf("looked up %d users, want 2", lookups)
+	}
+
+	filter.HasAttachments = true
+
+	s.bulk = nil
+
+	if _, err := node.Purge(context.Background(), 1, 2, filter, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.bulkDeleted(); len(got) != 1 || !slices.Equal(got[0], []MessageID{106}) {
+		t.Fatalf("bulk deleted %v, want only the message with attachments", got)
+	}
+}
+
+func TestPurgeBatches(t *testing.T) {
+	history := testHistory(250)
+
+	s := newPurgeServer(t, history)
+	s.handleBulk(0)
+
+	node := newTestNode(t, s.mux)
+
+	var reports atomic.Int32
+
+	summary, err := node.Purge(context.Background(), 1, 2, PurgeFilter{}, 230, WithPurgeProgress(func(PurgeProgress) {
+		reports.Add(1)
+	}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.bulkDeleted()
+
+	if len(got) != 3 || len(got[0]) != 100 || len(got[1]) != 100 || len(got[2]) != 30 {
+		t.Fatalf("bulk deleted batches of %d, want 100, 100 and 30", len(got))
+	}
+
+	// The most recent messages are purged first, in batches following the history
+	if got[0][0] != 1249 || got[2][29] != 1020 {
+		t.Fatalf("batches run from %d to %d, want 1249 to 1020", got[0][0], got[2][29])
+	}
+
+	if summary.Scanned != 230 || summary.Deleted != 230 {
+		t.Fatalf("summary = %+v, want 230 scanned and deleted", summary.PurgeProgress)
+	}
+
+	if reports.Load() == 0 {
+		t.Fatal("progress was never reported")
+	}
+}
+
+func TestPurgeScanLimit(t *testing.T) {
+	s := newPurgeServer(t, testHistory(50))
+	s.handleBulk(0)
+
+	node := newTestNode(t, s.mux)
+
+	summary, err := node.Purge(context.Background(), 1, 2, PurgeFilter{}, 0, WithPurgeScanLimit(20))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Scanned != 20 || summary.Deleted != 20 {
+		t.Fatalf("summary = %+v, want 20 scanned and deleted", summary.PurgeProgress)
+	}
+}
+
+func TestPurgeFallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"method not allowed", http.StatusMethodNotAllowed},
+		{"not found in an existing channel", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPurgeServer(t, testHistory(10))
+			s.handleBulk(tt.status)
+			s.handleDelete(0)
+			s.mux.HandleFunc("GET /api/planets/1/channels/2", func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, Channel{ID: 2, PlanetID: 1})
+			})
+
+			node := newTestNode(t, s.mux)
+
+			summary, err := node.Purge(context.Background(), 1, 2, PurgeFilter{}, 0, WithPurgeConcurrency(3))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := s.individuallyDeleted(), messageIDs(testHistory(10)); !slices.Equal(got, want) {
+				t.Fatalf("deleted %v, want %v", got, want)
+			}
+
+			if summary.Deleted != 10 {
+				t.Fatalf("summary = %+v, want 10 deleted", summary.PurgeProgress)
+			}
+
+			if !node.noBulkDelete.Load() {
+				t.Fatal("missing bulk delete endpoint wasn't remembered")
+			}
+		})
+	}
+}
+
+func TestPurgeMissingChannel(t *testing.T) {
+	s := newPurgeServer(t, testHistory(10))
+	s.handleBulk(http.StatusNotFound)
+	s.handleDelete(0)
+	s.mux.HandleFunc("GET /api/planets/1/channels/2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	node := newTestNode(t, s.mux)
+
+	summary, err := node.Purge(context.Background(), 1, 2, PurgeFilter{}, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Failed != 10 || len(summary.Errors) != 1 || !IsNotFound(summary.Errors[0]) {
+		t.Fatalf("summary = %+v with errors %v, want 10 failed as not found", summary.PurgeProgress, summary.Errors)
+	}
+
+	if deleted := s.individuallyDeleted(); len(deleted) != 0 {
+		t.Fatalf("deleted %v individually for a missing channel", deleted)
+	}
+
+	if node.noBulkDelete.Load() {
+		t.Fatal("a missing channel was taken for a missing bulk delete endpoint")
+	}
+}
+
+func TestPurgeRateLimitRetry(t *testing.T) {
+	s := newPurgeServer(t, testHistory(3))
+	s.handleBulk(http.StatusMethodNotAllowed)
+	s.handleDelete(1)
+
+	node := newTestNode(t, s.mux)
+
+	summary, err := node.Purge(context.Background(), 1, 2, PurgeFilter{}, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.individuallyDeleted(), messageIDs(testHistory(3)); !slices.Equal(got, want) {
+		t.Fatalf("deleted %v, want %v after retrying", got, want)
+	}
+
+	if summary.Deleted != 3 || summary.Failed != 0 {
+		t.Fatalf("summary = %+v, want 3 deleted", summary.PurgeProgress)
+	}
+}
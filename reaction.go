@@ -0,0 +1,161 @@
+package valour
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EmojiRef refers to either a Unicode emoji or a planet's custom emoji
+type EmojiRef struct {
+	// Unicode is the emoji itself, such as "👍", for Unicode emojis
+	Unicode string
+
+	// ID is the custom emoji, for planet emojis
+	ID EmojiID
+}
+
+// CustomEmojiPrefix and CustomEmojiSuffix surround a custom emoji's ID, as in «e-123»,
+// both in message content and in reactions
+const (
+	CustomEmojiPrefix = "«e-"
+	CustomEmojiSuffix = "»"
+)
+
+func UnicodeEmoji(emoji string) EmojiRef {
+	return EmojiRef{Unicode: emoji}
+}
+
+func CustomEmoji(id EmojiID) EmojiRef {
+	return EmojiRef{ID: id}
+}
+
+// ParseEmojiRef parses the emoji of a reaction, as returned by EmojiRef.String
+func ParseEmojiRef(s string) EmojiRef {
+	if strings.HasPrefix(s, CustomEmojiPrefix) && strings.HasSuffix(s, CustomEmojiSuffix) {
+		id, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(s, CustomEmojiPrefix), CustomEmojiSuffix), 10, 64)
+
+		if err == nil {
+			return CustomEmoji(EmojiID(id))
+		}
+	}
+
+	return UnicodeEmoji(s)
+}
+
+// Ref returns a reference to the custom emoji, for reacting with it
+func (e Emoji) Ref() EmojiRef {
+	return CustomEmoji(e.ID)
+}
+
+// IsCustom checks whether the reference is to a planet's custom emoji
+func (r EmojiRef) IsCustom() bool {
+	return r.ID.IsValid()
+}
+
+// String returns the emoji in the form the API uses for reactions
+func (r EmojiRef) String() string {
+	if r.IsCustom() {
+		return CustomEmojiPrefix + r.ID.String() + CustomEmojiSuffix
+	}
+
+	return r.Unicode
+}
+
+// ReactionSummary is the reactions with a single emoji on a message
+type ReactionSummary struct {
+	Emoji EmojiRef
+	Count int
+
+	// Me is whether we reacted with this emoji
+	Me bool
+
+	// UserIDs are the users who reacted, in the order they reacted
+	UserIDs []UserID
+}
+
+// ReactionSummaries aggregates a message's reactions by emoji, in the order each emoji was first used.
+// me is used to fill in ReactionSummary.Me.
+func (m Message) ReactionSummaries(me UserID) []ReactionSummary {
+	var summaries []ReactionSummary
+
+	index := make(map[string]int)
+
+	for _, r := range m.Reactions {
+		i, ok := index[r.Emoji]
+
+		if !ok {
+			i = len(summaries)
+			index[r.Emoji] = i
+			summaries = append(summaries, ReactionSummary{Emoji: ParseEmojiRef(r.Emoji)})
+		}
+
+		s := &summaries[i]
+		s.Count++
+		s.UserIDs = append(s.UserIDs, r.AuthorUserID)
+
+		if r.AuthorUserID == me {
+			s.Me = true
+		}
+	}
+
+	return summaries
+}
+
+// Reacted checks whether a user reacted to the message with an emoji
+func (m Message) Reacted(userID UserID, emoji EmojiRef) bool {
+	return slices.ContainsFunc(m.Reactions, func(r Reaction) bool {
+		return r.AuthorUserID == userID && r.Emoji == emoji.String()
+	})
+}
+
+// AddReaction reacts to a message with an emoji
+func (n *Node) AddReaction(id MessageID, emoji EmojiRef) error {
+	return n.MessageReactionAdd(id, emoji.String())
+}
+
+// RemoveReaction removes our reaction with an emoji from a message
+func (n *Node) RemoveReaction(id MessageID, emoji EmojiRef) error {
+	return n.MessageReactionRemove(id, emoji.String())
+}
+
+// ReactionUsers returns the users who reacted to a message with an emoji, in the order they reacted
+func (n *Node) ReactionUsers(id MessageID, emoji EmojiRef) ([]UserID, error) {
+	m, err := n.Message(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var users []UserID
+
+	for _, r := range m.Reactions {
+		if r.Emoji == emoji.String() {
+			users = append(users, r.AuthorUserID)
+		}
+	}
+
+	return users, nil
+}
+
+// ToggleReaction removes our reaction with an emoji if we've reacted, or adds it otherwise.
+// It returns whether the reaction was added.
+func (n *Node) ToggleReaction(id MessageID, emoji EmojiRef) (bool, error) {
+	me, err := n.Me()
+
+	if err != nil {
+		return false, err
+	}
+
+	m, err := n.Message(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	if m.Reacted(me.ID, emoji) {
+		return false, n.RemoveReaction(id, emoji)
+	}
+
+	return true, n.AddReaction(id, emoji)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/auroradevllc/handler"
 	"github.com/auroradevllc/valourgo/signalr"
+	cmap "github.com/orcaman/concurrent-map/v2"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -31,6 +32,9 @@ type RTC struct {
 	client  *signalr.Client
 	handler handler.HandlerInterface
 	state   RTCState
+
+	// joined contains the channels joined on this connection
+	joined cmap.ConcurrentMap[ChannelID, struct{}]
 }
 
 type BaseRTCResponse struct {
@@ -49,6 +53,7 @@ func ConnectRTC(ctx context.Context, name, address string, handler handler.Handl
 
 	r := &RTC{
 		handler: handler,
+		joined:  cmap.NewStringer[ChannelID, struct{}](),
 	}
 
 	r.client = signalr.NewClient(address,
@@ -85,6 +90,30 @@ func (r *RTC) defaultHandler(target string, args []json.RawMessage) {
 		decodeAndCall[PlanetMemberUpdate](args[0], r.handler)
 	case "PlanetMember-Delete":
 		decodeAndCall[PlanetMemberDelete](args[0], r.handler)
+	case "PlanetRole-Update":
+		decodeAndCall[RoleUpdateEvent](args[0], r.handler)
+	case "PlanetRole-Delete":
+		decodeAndCall[RoleDeleteEvent](args[0], r.handler)
+	case "PermissionsNode-Update":
+		decodeAndCall[PermissionsNodeUpdateEvent](args[0], r.handler)
+	case "PermissionsNode-Delete":
+		decodeAndCall[PermissionsNodeDeleteEvent](args[0], r.handler)
+	case "PlanetBan-Update":
+		decodeAndCall[PlanetBanUpdate](args[0], r.handler)
+	case "PlanetBan-Delete":
+		decodeAndCall[PlanetBanDelete](args[0], r.handler)
+	case "UserFriend-Update":
+		decodeAndCall[FriendUpdateEvent](args[0], r.handler)
+	case "UserFriend-Delete":
+		decodeAndCall[FriendDeleteEvent](args[0], r.handler)
+	case "PlanetEmoji-Update":
+		decodeAndCall[EmojiUpdateEvent](args[0], r.handler)
+	case "PlanetEmoji-Delete":
+		decodeAndCall[EmojiDeleteEvent](args[0], r.handler)
+	case "MessagePin":
+		decodeAndCall[MessagePinEvent](args[0], r.handler)
+	case "MessageUnpin":
+		decodeAndCall[MessageUnpinEvent](args[0], r.handler)
 	case "MessageReactionAdd":
 		decodeAndCall[MessageReactionAddedEvent](args[0], r.handler)
 	case "MessageReactionRemove":
@@ -95,6 +124,8 @@ func (r *RTC) defaultHandler(target string, args []json.RawMessage) {
 		decodeAndCall[PlanetDeleteEvent](args[0], r.handler)
 	case "Channel-Update":
 		decodeAndCall[ChannelUpdateEvent](args[0], r.handler)
+	case "Channel-Delete":
+		decodeAndCall[ChannelDeleteEvent](args[0], r.handler)
 	default:
 		log.WithField("target", target).Debug("No handler registered for target")
 	}
@@ -216,7 +247,18 @@ func (r *RTC) JoinChannel(channel ChannelID) error {
 		return err
 	}
 
-	return r.checkInvokeError(res)
+	if err := r.checkInvokeError(res); err != nil {
+		return err
+	}
+
+	r.joined.Set(channel, struct{}{})
+
+	return nil
+}
+
+// Joined checks whether a channel has been joined on this connection
+func (r *RTC) Joined(channel ChannelID) bool {
+	return r.joined.Has(channel)
 }
 
 // LeaveChannel unsubscribes from channel updates/messages
@@ -227,7 +269,13 @@ func (r *RTC) LeaveChannel(channel ChannelID) error {
 		return err
 	}
 
-	return r.checkInvokeError(res)
+	if err := r.checkInvokeError(res); err != nil {
+		return err
+	}
+
+	r.joined.Remove(channel)
+
+	return nil
 }
 
 // checkInvokeError will validate a message, decoding it as an RTC Response, and returning an error if one occurred
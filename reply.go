@@ -0,0 +1,64 @@
+package valour
+
+import "slices"
+
+// Reply sends a simple text message replying to m
+func (m Message) Reply(c Messages, content string) (*Message, error) {
+	return m.ReplyComplex(c, SendMessageData{
+		Content: content,
+	})
+}
+
+// ReplyComplex sends a message with optional text, attachments, and embeds replying to m
+func (m Message) ReplyComplex(c Messages, send SendMessageData) (*Message, error) {
+	send.ReplyToID = Ref(m.ID)
+
+	return c.SendMessageComplex(m.PlanetID, m.ChannelID, send)
+}
+
+// IsReply checks whether m replies to another message
+func (m Message) IsReply() bool {
+	return m.ReplyToID != nil && m.ReplyToID.IsValid()
+}
+
+// ReplyChain returns the conversation m is part of, from the first message to m itself.
+// Parents included in ReplyTo are used where possible, the rest are fetched.
+// At most limit messages are returned, or the whole chain if limit is 0. A deleted parent ends the chain.
+func (m Message) ReplyChain(c Messages, limit int) ([]Message, error) {
+	chain := []Message{m}
+
+	seen := map[MessageID]struct{}{m.ID: {}}
+
+	for cur := m; cur.IsReply() && (limit <= 0 || len(chain) < limit); {
+		if _, ok := seen[*cur.ReplyToID]; ok {
+			break
+		}
+
+		var parent *Message
+
+		if cur.ReplyTo != nil && cur.ReplyTo.ID == *cur.ReplyToID {
+			parent = cur.ReplyTo
+		} else {
+			fetched, err := c.Message(*cur.ReplyToID)
+
+			if IsNotFound(err) {
+				break
+			}
+
+			if err != nil {
+				slices.Reverse(chain)
+				return chain, err
+			}
+
+			parent = fetched
+		}
+
+		seen[parent.ID] = struct{}{}
+		chain = append(chain, *parent)
+		cur = *parent
+	}
+
+	slices.Reverse(chain)
+
+	return chain, nil
+}
@@ -0,0 +1,141 @@
+package valour
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// replyMessages serves stored messages, recording which were fetched.
+// Methods not used for replies panic through the nil embedded Messages.
+type replyMessages struct {
+	messagesAPI
+
+	messages map[MessageID]Message
+	errors   map[MessageID]error
+	fetched  []MessageID
+}
+
+func (r *replyMessages) Message(id MessageID) (*Message, error) {
+	r.fetched = append(r.fetched, id)
+
+	if err, ok := r.errors[id]; ok {
+		return nil, err
+	}
+
+	m, ok := r.messages[id]
+
+	if !ok {
+		return nil, &StatusError{StatusCode: http.StatusNotFound}
+	}
+
+	return &m, nil
+}
+
+func replyTo(id, parent MessageID) Message {
+	m := Message{ID: id}
+
+	if parent != 0 {
+		m.ReplyToID = Ref(parent)
+	}
+
+	return m
+}
+
+func TestReplyChain(t *testing.T) {
+	embedded := replyTo(3, 2)
+	embedded.ReplyTo = Ref(replyTo(2, 1))
+
+	tests := []struct {
+		name     string
+		messages []Message
+		errors   map[MessageID]error
+		start    Message
+		limit    int
+		want     []MessageID
+		fetched  []MessageID
+		err      error
+	}{
+		{
+			name:  "not a reply",
+			start: replyTo(1, 0),
+			want:  []MessageID{1},
+		},
+		{
+			name:     "fetched parents",
+			messages: []Message{replyTo(1, 0), replyTo(2, 1)},
+			start:    replyTo(3, 2),
+			want:     []MessageID{1, 2, 3},
+			fetched:  []MessageID{2, 1},
+		},
+		{
+			name:     "embedded parent",
+			messages: []Message{replyTo(1, 0), replyTo(2, 1)},
+			start:    embedded,
+			want:     []MessageID{1, 2, 3},
+			fetched:  []MessageID{1},
+		},
+		{
+			name:     "deleted parent",
+			messages: []Message{replyTo(2, 1)},
+			start:    replyTo(3, 2),
+			want:     []MessageID{2, 3},
+			fetched:  []MessageID{2, 1},
+		},
+		{
+			name:     "cycle",
+			messages: []Message{replyTo(1, 2), replyTo(2, 1)},
+			start:    replyTo(1, 2),
+			want:     []MessageID{2, 1},
+			fetched:  []MessageID{2},
+		},
+		{
+			name:     "reply to itself",
+			messages: []Message{replyTo(1, 1)},
+			start:    replyTo(1, 1),
+			want:     []MessageID{1},
+		},
+		{
+			name:     "limit",
+			messages: []Message{replyTo(1, 0), replyTo(2, 1), replyTo(3, 2)},
+			start:    replyTo(4, 3),
+			limit:    2,
+			want:     []MessageID{3, 4},
+			fetched:  []MessageID{3},
+		},
+		{
+			name:     "error",
+			messages: []Message{replyTo(2, 1)},
+			errors:   map[MessageID]error{1: errors.New("unavailable")},
+			start:    replyTo(3, 2),
+			want:     []MessageID{2, 3},
+			fetched:  []MessageID{2, 1},
+			err:      errors.New("unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &replyMessages{messages: make(map[MessageID]Message), errors: tt.errors}
+
+			for _, m := range tt.messages {
+				c.messages[m.ID] = m
+			}
+
+			chain, err := tt.start.ReplyChain(c, tt.limit)
+
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+
+			if got := messageIDs(chain); !slices.Equal(got, tt.want) {
+				t.Fatalf("chain = %v, want %v", got, tt.want)
+			}
+
+			if !slices.Equal(c.fetched, tt.fetched) {
+				t.Fatalf("fetched %v, want %v", c.fetched, tt.fetched)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package valour
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
+	"slices"
 )
 
 type Role struct {
@@ -15,7 +17,7 @@ type Role struct {
 	ChatPermissions     int      `json:"chatPermissions"`
 	CategoryPermissions int      `json:"categoryPermissions"`
 	VoicePermissions    int      `json:"voicePermissions"`
-	Color               string   `json:"color"`
+	Color               Color    `json:"color"`
 	Bold                bool     `json:"bold"`
 	Italics             bool     `json:"italics"`
 	FlagBitIndex        int      `json:"flagBitIndex"`
@@ -25,9 +27,96 @@ type Role struct {
 
 type Roles interface {
 	Role(planetID PlanetID, roleID RoleID) (*Role, error)
+	CreateRole(planetID PlanetID, role Role) (*Role, error)
 	UpdateRole(planetID PlanetID, role Role) (*Role, error)
+	EditRole(planetID PlanetID, roleID RoleID, update *RoleUpdate) (*Role, error)
 	DeleteRole(planetID PlanetID, roleID RoleID) error
 	Roles(planetID PlanetID) ([]Role, error)
+	ReorderRoles(planetID PlanetID, order []RoleID) error
+	MoveRole(planetID PlanetID, roleID RoleID, position int) error
+}
+
+// RoleUpdate is a partial update to a role, only changing the fields that were set
+type RoleUpdate struct {
+	name                *string
+	color               *Color
+	bold                *bool
+	italics             *bool
+	permissions         *int64
+	chatPermissions     *int
+	categoryPermissions *int
+	voicePermissions    *int
+	anyoneCanMention    *bool
+	isAdmin             *bool
+}
+
+// NewRoleUpdate creates an empty role update, to be used with EditRole
+func NewRoleUpdate() *RoleUpdate {
+	return &RoleUpdate{}
+}
+
+func (u *RoleUpdate) Name(name string) *RoleUpdate {
+	u.name = &name
+	return u
+}
+
+func (u *RoleUpdate) Color(color Color) *RoleUpdate {
+	u.color = &color
+	return u
+}
+
+func (u *RoleUpdate) Bold(bold bool) *RoleUpdate {
+	u.bold = &bold
+	return u
+}
+
+func (u *RoleUpdate) Italics(italics bool) *RoleUpdate {
+	u.italics = &italics
+	return u
+}
+
+func (u *RoleUpdate) Permissions(permissions int64) *RoleUpdate {
+	u.permissions = &permissions
+	return u
+}
+
+func (u *RoleUpdate) ChatPermissions(permissions int) *RoleUpdate {
+	u.chatPermissions = &permissions
+	return u
+}
+
+func (u *RoleUpdate) CategoryPermissions(permissions int) *RoleUpdate {
+	u.categoryPermissions = &permissions
+	return u
+}
+
+func (u *RoleUpdate) VoicePermissions(permissions int) *RoleUpdate {
+	u.voicePermissions = &permissions
+	return u
+}
+
+func (u *RoleUpdate) AnyoneCanMention(anyoneCanMention bool) *RoleUpdate {
+	u.anyoneCanMention = &anyoneCanMention
+	return u
+}
+
+func (u *RoleUpdate) Admin(isAdmin bool) *RoleUpdate {
+	u.isAdmin = &isAdmin
+	return u
+}
+
+// Apply sets the fields of the update on a role
+func (u *RoleUpdate) Apply(r *Role) {
+	setIfNotNil(&r.Name, u.name)
+	setIfNotNil(&r.Color, u.color)
+	setIfNotNil(&r.Bold, u.bold)
+	setIfNotNil(&r.Italics, u.italics)
+	setIfNotNil(&r.Permissions, u.permissions)
+	setIfNotNil(&r.ChatPermissions, u.chatPermissions)
+	setIfNotNil(&r.CategoryPermissions, u.categoryPermissions)
+	setIfNotNil(&r.VoicePermissions, u.voicePermissions)
+	setIfNotNil(&r.AnyoneCanMention, u.anyoneCanMention)
+	setIfNotNil(&r.IsAdmin, u.isAdmin)
 }
 
 func (n *Node) Roles(planetID PlanetID) ([]Role, error) {
@@ -50,6 +139,44 @@ func (n *Node) Role(planetID PlanetID, roleID RoleID) (*Role, error) {
 	return &role, nil
 }
 
+// CreateRole creates a new role in a planet
+func (n *Node) CreateRole(planetID PlanetID, role Role) (*Role, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	role.PlanetID = planetID
+
+	var newRole Role
+
+	if err := node.requestJSON(http.MethodPost, planetID.Route("roles"), role, &newRole); err != nil {
+		return nil, err
+	}
+
+	return &newRole, nil
+}
+
+// EditRole applies a partial update to a role, leaving fields that weren't set unchanged
+func (n *Node) EditRole(planetID PlanetID, roleID RoleID, update *RoleUpdate) (*Role, error) {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := node.Role(planetID, roleID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	update.Apply(role)
+
+	return node.UpdateRole(planetID, *role)
+}
+
 func (n *Node) UpdateRole(planetID PlanetID, role Role) (*Role, error) {
 	var newRole Role
 
@@ -73,3 +200,129 @@ func (n *Node) DeleteRole(planetID PlanetID, roleID RoleID) error {
 
 	return nil
 }
+
+// ReorderRoles sets the order of a planet's roles, from the highest (position 0) to the lowest.
+// The order must contain every role of the planet.
+func (n *Node) ReorderRoles(planetID PlanetID, order []RoleID) error {
+	node, err := n.NodeForPlanet(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	return node.requestNoContent(http.MethodPost, planetID.Route("roles", "order"), order)
+}
+
+// MoveRole moves a single role to a new position, shifting the roles between its old and new position
+func (n *Node) MoveRole(planetID PlanetID, roleID RoleID, position int) error {
+	roles, err := n.Roles(planetID)
+
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(roles, func(a, b Role) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	order := make([]RoleID, 0, len(roles))
+
+	for _, role := range roles {
+		if role.ID != roleID {
+			order = append(order, role.ID)
+		}
+	}
+
+	if len(order) == len(roles) {
+		return fmt.Errorf("role %s not found in planet %s", roleID, planetID)
+	}
+
+	position = max(0, min(position, len(order)))
+
+	return n.ReorderRoles(planetID, slices.Insert(order, position, roleID))
+}
+
+// roleFlagWords is the number of 64 bit words used to store role membership
+const roleFlagWords = 4
+
+// MaxRoleFlagIndex is the largest Role.FlagBitIndex a planet can have
+const MaxRoleFlagIndex = roleFlagWords*64 - 1
+
+// RoleMembership is the set of roles a member holds, with each role stored as the bit at its Role.FlagBitIndex
+type RoleMembership struct {
+	Rf0 uint64 `json:"rf0"`
+	Rf1 uint64 `json:"rf1"`
+	Rf2 uint64 `json:"rf2"`
+	Rf3 uint64 `json:"rf3"`
+}
+
+func (m *RoleMembership) word(index int) *uint64 {
+	switch index / 64 {
+	case 0:
+		return &m.Rf0
+	case 1:
+		return &m.Rf1
+	case 2:
+		return &m.Rf2
+	case 3:
+		return &m.Rf3
+	}
+
+	return nil
+}
+
+// Has checks whether the bit at a role flag index is set
+func (m RoleMembership) Has(index int) bool {
+	w := m.word(index)
+
+	if index < 0 || w == nil {
+		return false
+	}
+
+	return *w&(1<<(index%64)) != 0
+}
+
+// Set sets or clears the bit at a role flag index
+func (m *RoleMembership) Set(index int, has bool) {
+	w := m.word(index)
+
+	if index < 0 || w == nil {
+		return
+	}
+
+	if has {
+		*w |= 1 << (index % 64)
+	} else {
+		*w &^= 1 << (index % 64)
+	}
+}
+
+// Indexes returns every role flag index that is set
+func (m RoleMembership) Indexes() []int {
+	var indexes []int
+
+	for i := 0; i <= MaxRoleFlagIndex; i++ {
+		if m.Has(i) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// Roles returns the roles held out of a planet's roles, ordered by position
+func (m RoleMembership) Roles(roles []Role) []Role {
+	var held []Role
+
+	for _, role := range roles {
+		if m.Has(role.FlagBitIndex) {
+			held = append(held, role)
+		}
+	}
+
+	slices.SortFunc(held, func(a, b Role) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	return held
+}
@@ -0,0 +1,118 @@
+package valour
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestRoleMembershipBits(t *testing.T) {
+	tests := []struct {
+		index int
+		want  RoleMembership
+	}{
+		{index: 0, want: RoleMembership{Rf0: 1}},
+		{index: 63, want: RoleMembership{Rf0: 1 << 63}},
+		{index: 64, want: RoleMembership{Rf1: 1}},
+		{index: 127, want: RoleMembership{Rf1: 1 << 63}},
+		{index: 128, want: RoleMembership{Rf2: 1}},
+		{index: 192, want: RoleMembership{Rf3: 1}},
+		{index: MaxRoleFlagIndex, want: RoleMembership{Rf3: 1 << 63}},
+		{index: -1},
+		{index: MaxRoleFlagIndex + 1},
+		{index: 1000},
+	}
+
+	for _, tt := range tests {
+		var m RoleMembership
+
+		m.Set(tt.index, true)
+
+		if m != tt.want {
+			t.Errorf("Set(%d) = %+v, want %+v", tt.index, m, tt.want)
+		}
+
+		if valid := tt.want != (RoleMembership{}); m.Has(tt.index) != valid {
+			t.Errorf("Has(%d) = %v, want %v", tt.index, m.Has(tt.index), valid)
+		}
+
+		// Neighbouring bits, which may be in another word, are unaffected
+		if m.Has(tt.index-1) || m.Has(tt.index+1) {
+			t.Errorf("Set(%d) also set a neighbouring index", tt.index)
+		}
+
+		m.Set(tt.index, false)
+
+		if m != (RoleMembership{}) {
+			t.Errorf("clearing %d left %+v", tt.index, m)
+		}
+	}
+}
+
+func TestRoleMembershipIndexes(t *testing.T) {
+	var m RoleMembership
+
+	want := []int{0, 63, 64, 200, MaxRoleFlagIndex}
+
+	for _, i := range want {
+		m.Set(i, true)
+	}
+
+	if got := m.Indexes(); !slices.Equal(got, want) {
+		t.Fatalf("Indexes = %v, want %v", got, want)
+	}
+}
+
+func TestMemberRoles(t *testing.T) {
+	roles := []Role{
+		{ID: 1, Position: 3, FlagBitIndex: 0},
+		{ID: 2, Position: 1, FlagBitIndex: 64},
+		{ID: 3, Position: 2, FlagBitIndex: 255},
+		{ID: 4, Position: 0, FlagBitIndex: 5},
+	}
+
+	var member Member
+
+	for _, i := range []int{0, 64, 255} {
+		member.RoleMembership.Set(i, true)
+	}
+
+	// Held roles are ordered by position, leaving out the role that isn't held
+	if got, want := member.RoleIDs(roles), []RoleID{2, 3, 1}; !slices.Equal(got, want) {
+		t.Fatalf("RoleIDs = %v, want %v", got, want)
+	}
+
+	if !member.HasRole(roles[2]) || member.HasRole(roles[3]) {
+		t.Fatal("HasRole doesn't match the membership bits")
+	}
+}
+
+func TestMemberRoleMembershipJSON(t *testing.T) {
+	data := `{"id":1,"roleMembership":{"rf0":9223372036854775809,"rf1":1,"rf2":0,"rf3":9223372036854775808}}`
+
+	var member Member
+
+	if err := json.Unmarshal([]byte(data), &member); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := member.RoleMembership.Indexes(), []int{0, 63, 64, MaxRoleFlagIndex}; !slices.Equal(got, want) {
+		t.Fatalf("decoded indexes %v, want %v", got, want)
+	}
+
+	b, err := json.Marshal(member)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Member
+
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.RoleMembership != member.RoleMembership {
+		t.Fatalf("round-trip = %+v, want %+v", decoded.RoleMembership, member.RoleMembership)
+	}
+}
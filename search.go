@@ -0,0 +1,84 @@
+package valour
+
+import (
+	"iter"
+	"net/http"
+	"time"
+)
+
+// maxSearchPageSize is the largest page of search results the API will return
+const maxSearchPageSize = 50
+
+// MessageSearch is a message search query. Every field that is set must match.
+type MessageSearch struct {
+	// Query is text the message content must contain
+	Query string `json:"query,omitempty"`
+
+	// ChannelID limits the search to a channel, required for direct and group channels
+	ChannelID ChannelID `json:"channelId,omitempty"`
+
+	AuthorID UserID `json:"authorUserId,omitempty"`
+
+	// Before and After limit results to messages sent within a time range
+	Before *time.Time `json:"before,omitempty"`
+	After  *time.Time `json:"after,omitempty"`
+
+	HasAttachments bool `json:"hasAttachments,omitempty"`
+}
+
+type messageSearchRequest struct {
+	MessageSearch
+
+	Skip int `json:"skip"`
+	Take int `json:"take"`
+}
+
+// SearchMessagesPage retrieves a single page of messages matching a search, newest first.
+// Direct and group channels use NullPlanetID with MessageSearch.ChannelID set.
+func (n *Node) SearchMessagesPage(planetID PlanetID, search MessageSearch, skip, take int) (*PagedResponse[Message], error) {
+	node, err := n.nodeForChannel(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	uri := planetID.Route("messages", "search")
+
+	if !planetID.IsValid() {
+		uri = search.ChannelID.Route("messages", "search")
+	}
+
+	req := messageSearchRequest{
+		MessageSearch: search,
+		Skip:          skip,
+		Take:          clampPageSize(take, maxSearchPageSize),
+	}
+
+	var page PagedResponse[Message]
+
+	if err := node.requestJSON(http.MethodPost, uri, req, &page); err != nil {
+		return nil, err
+	}
+
+	for i := range page.Items {
+		if err := page.Items[i].decodeAttachments(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &page, nil
+}
+
+// SearchMessages retrieves up to limit messages matching a search, newest first.
+// A limit of 0 retrieves every match.
+func (n *Node) SearchMessages(planetID PlanetID, search MessageSearch, limit uint) ([]Message, error) {
+	return collect(limited(n.SearchMessagesIter(planetID, search), limit))
+}
+
+// SearchMessagesIter iterates over the messages matching a search, newest first, requesting pages as they're needed.
+// Iteration stops after the first error.
+func (n *Node) SearchMessagesIter(planetID PlanetID, search MessageSearch) iter.Seq2[Message, error] {
+	return pages(maxSearchPageSize, func(skip, take int) (*PagedResponse[Message], error) {
+		return n.SearchMessagesPage(planetID, search, skip, take)
+	})
+}
@@ -0,0 +1,370 @@
+package valour
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned when a channel's send queue is full and the policy is QueueDropNewest
+	ErrQueueFull = errors.New("send queue is full")
+	// ErrSendDropped resolves the future of a queued send removed to make room with QueueDropOldest
+	ErrSendDropped = errors.New("send was dropped from a full queue")
+	// ErrSenderClosed is returned when sending through a closed Sender
+	ErrSenderClosed = errors.New("sender is closed")
+)
+
+// QueuePolicy decides what happens when a send is queued for a channel with a full queue
+type QueuePolicy int
+
+const (
+	// QueueBlock waits until the queue has room, or the context is done
+	QueueBlock QueuePolicy = iota
+	// QueueDropNewest rejects the new send with ErrQueueFull
+	QueueDropNewest
+	// QueueDropOldest removes the oldest queued send with the lowest priority, if it's not above the new send's
+	QueueDropOldest
+)
+
+const (
+	defaultSenderConcurrency = 4
+	defaultSenderQueueSize   = 100
+)
+
+// Sender queues outgoing messages, sending the messages of each channel one at a time in order
+// while sending to several channels at once.
+type Sender struct {
+	messages    Messages
+	concurrency int
+	queueSize   int
+	policy      QueuePolicy
+
+	mu     sync.Mutex
+	queues map[ChannelID]*sendQueue
+	seq    uint64
+	closed bool
+
+	sem     chan struct{}
+	workers sync.WaitGroup
+}
+
+type SenderOption func(s *Sender)
+
+// WithSenderConcurrency sets how many channels are sent to at once, defaulting to 4
+func WithSenderConcurrency(n int) SenderOption {
+	return func(s *Sender) {
+		s.concurrency = n
+	}
+}
+
+// WithSenderQueueSize sets how many sends can be queued for each channel, defaulting to 100
+func WithSenderQueueSize(n int) SenderOption {
+	return func(s *Sender) {
+		s.queueSize = n
+	}
+}
+
+// WithSenderQueuePolicy sets what happens when a channel's queue is full, defaulting to QueueBlock
+func WithSenderQueuePolicy(policy QueuePolicy) SenderOption {
+	return func(s *Sender) {
+		s.policy = policy
+	}
+}
+
+func NewSender(messages Messages, opts ...SenderOption) *Sender {
+	s := &Sender{
+		messages:    messages,
+		concurrency: defaultSenderConcurrency,
+		queueSize:   defaultSenderQueueSize,
+		queues:      make(map[ChannelID]*sendQueue),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.concurrency = max(s.concurrency, 1)
+	s.queueSize = max(s.queueSize, 1)
+	s.sem = make(chan struct{}, s.concurrency)
+
+	return s
+}
+
+type SendOption func(i *sendItem)
+
+// WithSendPriority sends the message ahead of queued messages with a lower priority in the same channel.
+// The default priority is 0.
+func WithSendPriority(priority int) SendOption {
+	return func(i *sendItem) {
+		i.priority = priority
+	}
+}
+
+// WithSendCoalesce replaces a queued send with the same key in the same channel, rather than queueing another.
+// This suits status messages where only the latest is worth sending. Both futures resolve to the same message.
+func WithSendCoalesce(key string) SendOption {
+	return func(i *sendItem) {
+		i.key = key
+	}
+}
+
+// SendFuture is the pending result of a queued send
+type SendFuture struct {
+	done    chan struct{}
+	message *Message
+	err     error
+}
+
+// Done is closed once the send has finished
+func (f *SendFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the send to finish, returning the created message
+func (f *SendFuture) Wait(ctx context.Context) (*Message, error) {
+	select {
+	case <-f.done:
+		return f.message, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *SendFuture) resolve(m *Message, err error) {
+	f.message = m
+	f.err = err
+	close(f.done)
+}
+
+type sendItem struct {
+	planetID PlanetID
+	data     SendMessageData
+	priority int
+	key      string
+	seq      uint64
+	futures  []*SendFuture
+}
+
+type sendQueue struct {
+	// items are ordered by priority, then by when they were queued
+	items   []*sendItem
+	running bool
+
+	// space is closed and replaced whenever an item leaves the queue
+	space chan struct{}
+}
+
+// Send queues a simple text message
+func (s *Sender) Send(ctx context.Context, planetID PlanetID, channelID ChannelID, content string, opts ...SendOption) (*SendFuture, error) {
+	return s.SendComplex(ctx, planetID, channelID, SendMessageData{Content: content}, opts...)
+}
+
+// SendComplex queues a message with optional text, attachments, and embeds.
+// The context only applies to waiting for room in the queue, not the send itself.
+func (s *Sender) SendComplex(ctx context.Context, planetID PlanetID, channelID ChannelID, data SendMessageData, opts ...SendOption) (*SendFuture, error) {
+	item := &sendItem{
+		planetID: planetID,
+		data:     data,
+	}
+
+	for _, opt := range opts {
+		opt(item)
+	}
+
+	future := &SendFuture{done: make(chan struct{})}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrSenderClosed
+	}
+
+	q := s.queue(channelID)
+
+	if item.key != "" {
+		if i := slices.IndexFunc(q.items, func(it *sendItem) bool { return it.key == item.key }); i != -1 {
+			s.coalesce(q, i, item, future)
+			return future, nil
+		}
+	}
+
+	for len(q.items) >= s.queueSize {
+		switch s.policy {
+		case QueueDropNewest:
+			return nil, ErrQueueFull
+		case QueueDropOldest:
+			if !s.dropOldest(q, item.priority) {
+				return nil, ErrQueueFull
+			}
+		default:
+			space := q.space
+
+			s.mu.Unlock()
+
+			select {
+			case <-space:
+			case <-ctx.Done():
+				s.mu.Lock()
+				return nil, ctx.Err()
+			}
+
+			s.mu.Lock()
+
+			if s.closed {
+				return nil, ErrSenderClosed
+			}
+
+			// The queue may have been removed while it was empty
+			q = s.queue(channelID)
+		}
+	}
+
+	s.seq++
+	item.seq = s.seq
+	item.futures = []*SendFuture{future}
+
+	s.insert(q, item)
+
+	if !q.running {
+		q.running = true
+		s.workers.Add(1)
+
+		go s.run(channelID, q)
+	}
+
+	return future, nil
+}
+
+// Close stops accepting sends and waits for the queued sends to finish, or the context to be done
+func (s *Sender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+
+	// Wake up any sends waiting for room, so they see the sender is closed
+	for _, q := range s.queues {
+		s.notifySpace(q)
+	}
+
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// queue returns the queue of a channel, creating it if needed. s.mu must be held.
+func (s *Sender) queue(channelID ChannelID) *sendQueue {
+	q, ok := s.queues[channelID]
+
+	if !ok {
+		q = &sendQueue{space: make(chan struct{})}
+		s.queues[channelID] = q
+	}
+
+	return q
+}
+
+// insert adds an item after the items with the same or a higher priority
+func (s *Sender) insert(q *sendQueue, item *sendItem) {
+	i := slices.IndexFunc(q.items, func(it *sendItem) bool {
+		return it.priority < item.priority
+	})
+
+	if i == -1 {
+		i = len(q.items)
+	}
+
+	q.items = slices.Insert(q.items, i, item)
+}
+
+// coalesce replaces the queued item at i with a newer send of the same key
+func (s *Sender) coalesce(q *sendQueue, i int, item *sendItem, future *SendFuture) {
+	existing := q.items[i]
+	existing.planetID = item.planetID
+	existing.data = item.data
+	existing.futures = append(existing.futures, future)
+
+	if item.priority > existing.priority {
+		existing.priority = item.priority
+
+		// Re-position the item for its new priority
+		q.items = slices.Delete(q.items, i, i+1)
+		s.insert(q, existing)
+	}
+}
+
+// dropOldest removes the oldest item with the lowest priority, if it's not above priority
+func (s *Sender) dropOldest(q *sendQueue, priority int) bool {
+	last := q.items[len(q.items)-1]
+
+	if last.priority > priority {
+		return false
+	}
+
+	i := slices.IndexFunc(q.items, func(it *sendItem) bool {
+		return it.priority == last.priority
+	})
+
+	dropped := q.items[i]
+	q.items = slices.Delete(q.items, i, i+1)
+
+	for _, f := range dropped.futures {
+		f.resolve(nil, ErrSendDropped)
+	}
+
+	s.notifySpace(q)
+
+	return true
+}
+
+func (s *Sender) notifySpace(q *sendQueue) {
+	close(q.space)
+	q.space = make(chan struct{})
+}
+
+// run sends a channel's queued messages in order until the queue is empty
+func (s *Sender) run(channelID ChannelID, q *sendQueue) {
+	defer s.workers.Done()
+
+	for {
+		s.sem <- struct{}{}
+
+		s.mu.Lock()
+
+		if len(q.items) == 0 {
+			q.running = false
+			delete(s.queues, channelID)
+			s.mu.Unlock()
+
+			<-s.sem
+			return
+		}
+
+		item := q.items[0]
+		q.items = q.items[1:]
+
+		s.notifySpace(q)
+		s.mu.Unlock()
+
+		m, err := s.messages.SendMessageComplex(item.planetID, channelID, item.data)
+
+		<-s.sem
+
+		for _, f := range item.futures {
+			f.resolve(m, err)
+		}
+	}
+}
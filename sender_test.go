@@ -0,0 +1,341 @@
+package valour
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// messagesAPI lets fakeMessages embed Messages, whose own Messages method clashes with the field name
+type messagesAPI = Messages
+
+// fakeMessages records sent messages. While gate is set, each send waits for it after reporting on started.
+// Methods not used by the sender panic through the nil embedded Messages.
+type fakeMessages struct {
+	messagesAPI
+
+	gate    chan struct{}
+	started chan string
+
+	mu   sync.Mutex
+	sent map[ChannelID][]string
+	id   MessageID
+}
+
+func newFakeMessages(gated bool) *fakeMessages {
+	f := &fakeMessages{
+		started: make(chan string, 100),
+		sent:    make(map[ChannelID][]string),
+	}
+
+	if gated {
+		f.gate = make(chan struct{})
+	}
+
+	return f
+}
+
+func (f *fakeMessages) SendMessageComplex(planetID PlanetID, channelID ChannelID, send SendMessageData) (*Message, error) {
+	if f.gate != nil {
+		f.started <- send.Content
+		<-f.gate
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.id++
+	f.sent[channelID] = append(f.sent[channelID], send.Content)
+
+	return &Message{ID: f.id, PlanetID: planetID, ChannelID: channelID, Content: send.Content}, nil
+}
+
+func (f *fakeMessages) sentTo(channelID ChannelID) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.sent[channelID])
+}
+
+// waitStarted waits for the send of content to start, leaving later sends queued behind it
+func (f *fakeMessages) waitStarted(t *testing.T, content string) {
+	t.Helper()
+
+	select {
+	case got := <-f.started:
+		if got != content {
+			t.Fatalf("started sending %q, want %q", got, content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %q to be sent", content)
+	}
+}
+
+func mustSend(t *testing.T, s *Sender, channelID ChannelID, content string, opts ...SendOption) *SendFuture {
+	t.Helper()
+
+	f, err := s.Send(context.Background(), 1, channelID, content, opts...)
+
+	if err != nil {
+		t.Fatalf("Send(%q) = %v", content, err)
+	}
+
+	return f
+}
+
+func wait(t *testing.T, f *SendFuture) (*Message, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return f.Wait(ctx)
+}
+
+func closeSender(t *testing.T, s *Sender) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenderChannelOrder(t *testing.T) {
+	messages := newFakeMessages(false)
+	s := NewSender(messages, WithSenderConcurrency(2))
+
+	const perChannel = 50
+
+	channels := []ChannelID{1, 2, 3, 4, 5}
+
+	var wg sync.WaitGroup
+
+	for _, channelID := range channels {
+		wg.Go(func() {
+			for i := range perChannel {
+				mustSend(t, s, channelID, fmt.Sprint(i))
+			}
+		})
+	}
+
+	wg.Wait()
+	closeSender(t, s)
+
+	for _, channelID := range channels {
+		got := messages.sentTo(channelID)
+
+		if len(got) != perChannel {
+			t.Fatalf("channel %d got %d messages, want %d", channelID, len(got), perChannel)
+		}
+
+		for i, content := range got {
+			if content != fmt.Sprint(i) {
+				t.Fatalf("channel %d message %d is %q, sent out of order: %v", channelID, i, content, got)
+			}
+		}
+	}
+}
+
+func TestSenderPriority(t *testing.T) {
+	messages := newFakeMessages(true)
+	s := NewSender(messages)
+
+	mustSend(t, s, 1, "first")
+	messages.waitStarted(t, "first")
+
+	mustSend(t, s, 1, "low 1")
+	mustSend(t, s, 1, "low 2")
+	mustSend(t, s, 1, "high", WithSendPriority(1))
+	mustSend(t, s, 1, "low 3")
+	mustSend(t, s, 1, "high 2", WithSendPriority(1))
+
+	close(messages.gate)
+	closeSender(t, s)
+
+	want := []string{"first", "high", "high 2", "low 1", "low 2", "low 3"}
+
+	if got := messages.sentTo(1); !slices.Equal(got, want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+}
+
+func TestSenderCoalesce(t *testing.T) {
+	messages := newFakeMessages(true)
+	s := NewSender(messages)
+
+	mustSend(t, s, 1, "first")
+	messages.waitStarted(t, "first")
+
+	a := mustSend(t, s, 1, "status a", WithSendCoalesce("status"))
+	other := mustSend(t, s, 1, "other")
+	b := mustSend(t, s, 1, "status b", WithSendCoalesce("status"), WithSendPriority(1))
+
+	close(messages.gate)
+
+	ma, errA := wait(t, a)
+	mb, errB := wait(t, b)
+
+	if errA != nil || errB != nil {
+		t.Fatalf("coalesced sends failed: %v, %v", errA, errB)
+	}
+
+	if ma != mb || ma.Content != "status b" {
+		t.Fatalf("coalesced futures resolved to %+v and %+v, want the same latest message", ma, mb)
+	}
+
+	if _, err := wait(t, other); err != nil {
+		t.Fatal(err)
+	}
+
+	closeSender(t, s)
+
+	// The raised priority moves the coalesced send ahead of the one queued between them
+	want := []string{"first", "status b", "other"}
+
+	if got := messages.sentTo(1); !slices.Equal(got, want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+}
+
+func TestSenderDropNewest(t *testing.T) {
+	messages := newFakeMessages(true)
+	s := NewSender(messages, WithSenderQueueSize(1), WithSenderQueuePolicy(QueueDropNewest))
+
+	mustSend(t, s, 1, "first")
+	messages.waitStarted(t, "first")
+
+	queued := mustSend(t, s, 1, "queued")
+
+	if _, err := s.Send(context.Background(), 1, 1, "rejected"); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Send on a full queue = %v, want ErrQueueFull", err)
+	}
+
+	// Other channels have their own queues
+	mustSend(t, s, 2, "other channel")
+
+	close(messages.gate)
+
+	if _, err := wait(t, queued); err != nil {
+		t.Fatal(err)
+	}
+
+	closeSender(t, s)
+
+	if got, want := messages.sentTo(1), []string{"first", "queued"}; !slices.Equal(got, want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+}
+
+func TestSenderDropOldest(t *testing.T) {
+	messages := newFakeMessages(true)
+	s := NewSender(messages, WithSenderQueueSize(2), WithSenderQueuePolicy(QueueDropOldest))
+
+	mustSend(t, s, 1, "first")
+	messages.waitStarted(t, "first")
+
+	important := mustSend(t, s, 1, "important", WithSendPriority(1))
+	oldest := mustSend(t, s, 1, "oldest")
+	newest := mustSend(t, s, 1, "newest")
+
+	if _, err := wait(t, oldest); !errors.Is(err, ErrSendDropped) {
+		t.Fatalf("oldest send resolved with %v, want ErrSendDropped", err)
+	}
+
+	// A queue of higher priority sends is kept, rejecting the lower priority send
+	if _, err := s.Send(context.Background(), 1, 1, "lower", WithSendPriority(-1)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("lower priority Send = %v, want ErrQueueFull", err)
+	}
+
+	close(messages.gate)
+
+	for _, f := range []*SendFuture{important, newest} {
+		if _, err := wait(t, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closeSender(t, s)
+
+	if got, want := messages.sentTo(1), []string{"first", "important", "newest"}; !slices.Equal(got, want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+}
+
+func TestSenderBlockContext(t *testing.T) {
+	messages := newFakeMessages(true)
+	s := NewSender(messages, WithSenderQueueSize(1))
+
+	mustSend(t, s, 1, "first")
+	messages.waitStarted(t, "first")
+	mustSend(t, s, 1, "queued")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := s.Send(ctx, 1, 1, "blocked"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Send on a full queue = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(messages.gate)
+	closeSender(t, s)
+}
+
+func TestSenderCloseWhileBlocked(t *testing.T) {
+	messages := newFakeMessages(true)
+	s := NewSender(messages, WithSenderQueueSize(1))
+
+	mustSend(t, s, 1, "first")
+	messages.waitStarted(t, "first")
+	queued := mustSend(t, s, 1, "queued")
+
+	blocked := make(chan error, 1)
+
+	go func() {
+		_, err := s.Send(context.Background(), 1, 1, "blocked")
+		blocked <- err
+	}()
+
+	// Give the send time to start waiting for room
+	time.Sleep(50 * time.Millisecond)
+
+	// Close can't finish while a send is in progress, but stops the blocked send
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := s.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close during a send = %v, want context.DeadlineExceeded", err)
+	}
+
+	select {
+	case err := <-blocked:
+		if !errors.Is(err, ErrSenderClosed) {
+			t.Fatalf("blocked Send = %v, want ErrSenderClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocked Send wasn't woken by Close")
+	}
+
+	if _, err := s.Send(context.Background(), 1, 2, "late"); !errors.Is(err, ErrSenderClosed) {
+		t.Fatalf("Send after Close = %v, want ErrSenderClosed", err)
+	}
+
+	close(messages.gate)
+
+	// Queued sends still finish after closing
+	if _, err := wait(t, queued); err != nil {
+		t.Fatal(err)
+	}
+
+	closeSender(t, s)
+
+	if got, want := messages.sentTo(1), []string{"first", "queued"}; !slices.Equal(got, want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+}
@@ -24,10 +24,14 @@ const (
 	NullMemberID  = MemberID(0)
 	NullMessageID = MessageID(0)
 	NullRoleID    = RoleID(0)
+	NullBanID     = BanID(0)
+
+	NullPermissionsNodeID = PermissionsNodeID(0)
+	NullUserFriendID      = UserFriendID(0)
 )
 
 type SnowflakeType interface {
-	Snowflake | PlanetID | ChannelID | UserID | MemberID | MessageID | RoleID
+	Snowflake | PlanetID | ChannelID | UserID | MemberID | MessageID | RoleID | BanID | PermissionsNodeID | UserFriendID
 }
 
 func ParseSnowflake[V SnowflakeType](in string) (V, error) {
@@ -55,6 +59,18 @@ func (i Snowflake) Time() time.Time {
 	return time.Unix(0, int64(unixnano))
 }
 
+// SnowflakeFromTime returns the lowest snowflake created at t, for comparing IDs against a time.
+// Times before the Epoch return NullSnowflake.
+func SnowflakeFromTime(t time.Time) Snowflake {
+	ms := t.UnixMilli() - Epoch.Milliseconds()
+
+	if ms < 0 {
+		return NullSnowflake
+	}
+
+	return Snowflake(ms) << lowerBits
+}
+
 func (i Snowflake) Generator() uint16 {
 	return uint16((i >> sequenceBits) & 0x3FF)
 }
@@ -94,6 +110,17 @@ func (i ChannelID) IsValid() bool {
 	return Snowflake(i).IsValid()
 }
 
+func (i ChannelID) Route(path ...string) string {
+	p := []string{
+		apiChannelBase,
+		i.String(),
+	}
+
+	p = append(p, path...)
+
+	return strings.Join(p, "/")
+}
+
 type UserID Snowflake
 
 func (i UserID) String() string {
@@ -125,6 +152,17 @@ func (i MemberID) IsValid() bool {
 	return Snowflake(i).IsValid()
 }
 
+func (i MemberID) Route(path ...string) string {
+	p := []string{
+		apiMemberBase,
+		i.String(),
+	}
+
+	p = append(p, path...)
+
+	return strings.Join(p, "/")
+}
+
 type MessageID Snowflake
 
 func (i MessageID) String() string {
@@ -165,3 +203,66 @@ func (i EmojiID) String() string {
 func (i EmojiID) IsValid() bool {
 	return Snowflake(i).IsValid()
 }
+
+func (i EmojiID) Route(path ...string) string {
+	p := []string{
+		apiEmojiBase,
+		i.String(),
+	}
+
+	p = append(p, path...)
+
+	return strings.Join(p, "/")
+}
+
+type BanID Snowflake
+
+func (i BanID) String() string {
+	return Snowflake(i).String()
+}
+
+func (i BanID) IsValid() bool {
+	return Snowflake(i).IsValid()
+}
+
+func (i BanID) Route(path ...string) string {
+	p := []string{
+		apiBanBase,
+		i.String(),
+	}
+
+	p = append(p, path...)
+
+	return strings.Join(p, "/")
+}
+
+type PermissionsNodeID Snowflake
+
+func (i PermissionsNodeID) String() string {
+	return Snowflake(i).String()
+}
+
+func (i PermissionsNodeID) IsValid() bool {
+	return Snowflake(i).IsValid()
+}
+
+func (i PermissionsNodeID) Route(path ...string) string {
+	p := []string{
+		apiPermissionsNodeBase,
+		i.String(),
+	}
+
+	p = append(p, path...)
+
+	return strings.Join(p, "/")
+}
+
+type UserFriendID Snowflake
+
+func (i UserFriendID) String() string {
+	return Snowflake(i).String()
+}
+
+func (i UserFriendID) IsValid() bool {
+	return Snowflake(i).IsValid()
+}
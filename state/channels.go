@@ -0,0 +1,206 @@
+package state
+
+import (
+	"cmp"
+	"slices"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+// ChannelNode is a channel and its ordered children in a planet's channel tree
+type ChannelNode struct {
+	valour.Channel
+	Children []*ChannelNode
+}
+
+// ChannelTree returns a planet's top level channels, with their children, in the order the client displays them.
+// Channels whose parent is unknown are treated as top level channels.
+func (s *State) ChannelTree(planetID valour.PlanetID) ([]*ChannelNode, error) {
+	channels, err := s.Channels(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildChannelTree(channels), nil
+}
+
+// SortedChannels returns a planet's channels in the order the client displays them,
+// with each category followed by its children.
+func (s *State) SortedChannels(planetID valour.PlanetID) ([]valour.Channel, error) {
+	tree, err := s.ChannelTree(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]valour.Channel, 0)
+
+	var walk func(nodes []*ChannelNode)
+
+	walk = func(nodes []*ChannelNode) {
+		for _, node := range nodes {
+			sorted = append(sorted, node.Channel)
+			walk(node.Children)
+		}
+	}
+
+	walk(tree)
+
+	return sorted, nil
+}
+
+// ChannelAncestors returns the categories containing a channel, starting with its direct parent
+func (s *State) ChannelAncestors(planetID valour.PlanetID, channelID valour.ChannelID) ([]valour.Channel, error) {
+	channels, err := s.Channels(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[valour.ChannelID]valour.Channel, len(channels))
+
+	for _, ch := range channels {
+		byID[ch.ID] = ch
+	}
+
+	ch, ok := byID[channelID]
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	var ancestors []valour.Channel
+
+	seen := map[valour.ChannelID]bool{channelID: true}
+
+	for {
+		parent, ok := byID[ch.ParentID]
+
+		// Stop at a cycle from inconsistent data rather than repeating channels
+		if !ch.ParentID.IsValid() || !ok || seen[parent.ID] {
+			break
+		}
+
+		seen[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		ch = parent
+	}
+
+	return ancestors, nil
+}
+
+// DefaultChannel returns a planet's default channel
+func (s *State) DefaultChannel(planetID valour.PlanetID) (*valour.Channel, error) {
+	channels, err := s.Channels(planetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ch := range channels {
+		if ch.IsDefault {
+			return &ch, nil
+		}
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func buildChannelTree(channels []valour.Channel) []*ChannelNode {
+	nodes := make(map[valour.ChannelID]*ChannelNode, len(channels))
+
+	for _, ch := range channels {
+		nodes[ch.ID] = &ChannelNode{Channel: ch}
+	}
+
+	parents := make(map[valour.ChannelID]valour.ChannelID, len(channels))
+
+	for _, ch := range channels {
+		if _, ok := nodes[ch.ParentID]; ok && ch.ParentID.IsValid() && ch.ParentID != ch.ID {
+			parents[ch.ID] = ch.ParentID
+		}
+	}
+
+	// Channels in a parent cycle would never be reached from a root, so they become roots themselves
+	for id := range channelCycles(parents) {
+		delete(parents, id)
+	}
+
+	var roots []*ChannelNode
+
+	for _, ch := range channels {
+		node := nodes[ch.ID]
+
+		if parentID, ok := parents[ch.ID]; ok {
+			parent := nodes[parentID]
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	var sortNodes func(nodes []*ChannelNode)
+
+	sortNodes = func(nodes []*ChannelNode) {
+		slices.SortFunc(nodes, func(a, b *ChannelNode) int {
+			return compareChannels(a.Channel, b.Channel)
+		})
+
+		for _, node := range nodes {
+			sortNodes(node.Children)
+		}
+	}
+
+	sortNodes(roots)
+
+	return roots
+}
+
+// channelCycles returns the channels whose parent links form a cycle, which only happens with inconsistent data
+func channelCycles(parents map[valour.ChannelID]valour.ChannelID) map[valour.ChannelID]struct{} {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[valour.ChannelID]int, len(parents))
+	cycles := make(map[valour.ChannelID]struct{})
+
+	for id := range parents {
+		var path []valour.ChannelID
+
+		cur, ok := id, true
+
+		for ok && state[cur] == unvisited {
+			state[cur] = visiting
+			path = append(path, cur)
+
+			cur, ok = parents[cur]
+		}
+
+		// The walk reached a channel already on this path, everything from it onwards is a cycle
+		if ok && state[cur] == visiting {
+			for _, cycleID := range path[slices.Index(path, cur):] {
+				cycles[cycleID] = struct{}{}
+			}
+		}
+
+		for _, pathID := range path {
+			state[pathID] = visited
+		}
+	}
+
+	return cycles
+}
+
+// compareChannels orders sibling channels by their position within their parent
+func compareChannels(a, b valour.Channel) int {
+	return cmp.Or(
+		cmp.Compare(a.Position.LocalPosition, b.Position.LocalPosition),
+		cmp.Compare(a.RawPosition, b.RawPosition),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
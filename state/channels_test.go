@@ -0,0 +1,134 @@
+package state
+
+import (
+	"slices"
+	"testing"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+const testPlanet = valour.PlanetID(1)
+
+func newChannelState(t *testing.T, channels ...valour.Channel) *State {
+	t.Helper()
+
+	s := NewWithClient(&fakeClient{}, WithoutReadThrough())
+
+	for i := range channels {
+		channels[i].PlanetID = testPlanet
+
+		if err := s.Cabinet.ChannelSet(&channels[i], false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return s
+}
+
+func channel(id, parent valour.ChannelID, position int) valour.Channel {
+	return valour.Channel{
+		ID:       id,
+		ParentID: parent,
+		Position: valour.ChannelPosition{LocalPosition: position},
+	}
+}
+
+func channelIDs(channels []valour.Channel) []valour.ChannelID {
+	ids := make([]valour.ChannelID, len(channels))
+
+	for i, ch := range channels {
+		ids[i] = ch.ID
+	}
+
+	return ids
+}
+
+func TestSortedChannels(t *testing.T) {
+	s := newChannelState(t,
+		channel(20, 0, 1),
+		channel(10, 0, 0),
+		channel(12, 10, 1),
+		channel(11, 10, 0),
+		channel(21, 20, 0),
+		// Unknown parent, treated as top level
+		channel(30, 99, 2),
+	)
+
+	sorted, err := s.SortedChannels(testPlanet)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []valour.ChannelID{10, 11, 12, 20, 21, 30}
+
+	if got := channelIDs(sorted); !slices.Equal(got, want) {
+		t.Fatalf("sorted = %v, want %v", got, want)
+	}
+
+	tree, err := s.ChannelTree(testPlanet)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tree) != 3 || len(tree[0].Children) != 2 || len(tree[1].Children) != 1 {
+		t.Fatalf("unexpected tree shape: %d roots", len(tree))
+	}
+}
+
+func TestChannelTreeCycle(t *testing.T) {
+	s := newChannelState(t,
+		channel(1, 2, 0),
+		channel(2, 1, 1),
+		// Child of a channel in the cycle
+		channel(3, 2, 0),
+		channel(4, 0, 2),
+	)
+
+	sorted, err := s.SortedChannels(testPlanet)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []valour.ChannelID{1, 2, 3, 4}
+
+	if got := channelIDs(sorted); !slices.Equal(got, want) {
+		t.Fatalf("sorted = %v, want %v with the cycle promoted to roots", got, want)
+	}
+}
+
+func TestChannelAncestors(t *testing.T) {
+	s := newChannelState(t,
+		channel(1, 0, 0),
+		channel(2, 1, 0),
+		channel(3, 2, 0),
+		channel(10, 11, 0),
+		channel(11, 10, 0),
+	)
+
+	ancestors, err := s.ChannelAncestors(testPlanet, 3)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := channelIDs(ancestors); !slices.Equal(got, []valour.ChannelID{2, 1}) {
+		t.Fatalf("ancestors = %v, want [2 1]", got)
+	}
+
+	ancestors, err = s.ChannelAncestors(testPlanet, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := channelIDs(ancestors); !slices.Equal(got, []valour.ChannelID{11}) {
+		t.Fatalf("ancestors = %v, want [11] without repeating the cycle", got)
+	}
+
+	if _, err := s.ChannelAncestors(testPlanet, 99); err == nil {
+		t.Fatal("expected an error for an unknown channel")
+	}
+}
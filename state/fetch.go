@@ -0,0 +1,123 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	valour "github.com/auroradevllc/valourgo"
+	cmap "github.com/orcaman/concurrent-map/v2"
+)
+
+// defaultNegativeTTL is how long a not found response is remembered by default
+const defaultNegativeTTL = 30 * time.Second
+
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+// negativeCache remembers keys the API responded to with not found
+type negativeCache struct {
+	entries cmap.ConcurrentMap[string, negativeEntry]
+
+	// nextSweep is when set next removes expired entries, guarded by sweepMut
+	sweepMut  sync.Mutex
+	nextSweep time.Time
+}
+
+func newNegativeCache() *negativeCache {
+	return &negativeCache{
+		entries: cmap.New[negativeEntry](),
+	}
+}
+
+func (c *negativeCache) get(key string) error {
+	e, ok := c.entries.Get(key)
+
+	if !ok {
+		return nil
+	}
+
+	if time.Now().After(e.expires) {
+		c.entries.RemoveCb(key, func(_ string, v negativeEntry, exists bool) bool {
+			return exists && v.expires == e.expires
+		})
+
+		return nil
+	}
+
+	return e.err
+}
+
+func (c *negativeCache) set(key string, err error, ttl time.Duration) {
+	now := time.Now()
+
+	c.entries.Set(key, negativeEntry{
+		err:     err,
+		expires: now.Add(ttl),
+	})
+
+	c.sweep(now, ttl)
+}
+
+// sweep removes expired entries, at most once per interval so that keys which are never
+// looked up again don't stay in memory
+func (c *negativeCache) sweep(now time.Time, interval time.Duration) {
+	c.sweepMut.Lock()
+
+	if now.Before(c.nextSweep) {
+		c.sweepMut.Unlock()
+		return
+	}
+
+	c.nextSweep = now.Add(interval)
+	c.sweepMut.Unlock()
+
+	for t := range c.entries.IterBuffered() {
+		if !now.After(t.Val.expires) {
+			continue
+		}
+
+		c.entries.RemoveCb(t.Key, func(_ string, v negativeEntry, exists bool) bool {
+			return exists && v.expires == t.Val.expires
+		})
+	}
+}
+
+// fetch returns a value from the store, requesting it from the API on a miss.
+// Concurrent misses for the same key share a single request, and not found responses
+// are remembered for the negative TTL.
+func fetch[V any](s *State, key string, cached func() (V, error), request func() (V, error), set func(V)) (V, error) {
+	v, err := cached()
+
+	if err == nil || !s.readThrough {
+		return v, err
+	}
+
+	if err := s.negative.get(key); err != nil {
+		var zero V
+		return zero, err
+	}
+
+	res, err, shared := s.fetches.Do(key, func() (interface{}, error) {
+		v, err := request()
+
+		switch {
+		case err == nil:
+			set(v)
+		case valour.IsNotFound(err) && s.negativeTTL > 0:
+			s.negative.set(key, err, s.negativeTTL)
+		}
+
+		return v, err
+	})
+
+	// Callers sharing a request get their own copy from the store where possible
+	if shared && err == nil {
+		if v, err := cached(); err == nil {
+			return v, nil
+		}
+	}
+
+	return res.(V), err
+}
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"errors"
+	"iter"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+// fakeClient is a Client whose API methods are provided by the test.
+// Methods not overridden panic through the nil embedded Client.
+type fakeClient struct {
+	valour.Client
+
+	user    func(id valour.UserID) (*valour.User, error)
+	members []valour.Member
+}
+
+func (c *fakeClient) AddSyncHandler(fn interface{}) (rm func()) {
+	return func() {}
+}
+
+func (c *fakeClient) User(id valour.UserID) (*valour.User, error) {
+	return c.user(id)
+}
+
+func (c *fakeClient) PlanetMembersIter(planetID valour.PlanetID) iter.Seq2[valour.Member, error] {
+	return func(yield func(valour.Member, error) bool) {
+		for _, m := range c.members {
+			if m.PlanetID == planetID && !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestFetchDeduplicatesConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	s := NewWithClient(&fakeClient{
+		user: func(id valour.UserID) (*valour.User, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+			}
+
+			<-release
+
+			return &valour.User{ID: id, Name: "user"}, nil
+		},
+	})
+
+	const callers = 8
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, callers)
+
+	for range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			user, err := s.User(1)
+
+			if err == nil && user.ID != 1 {
+				err = errors.New("wrong user returned")
+			}
+
+			errs <- err
+		}()
+	}
+
+	<-started
+
+	// Give the other callers time to join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("requests = %d, want 1", n)
+	}
+
+	// Now served from the store
+	if _, err := s.User(1); err != nil || calls.Load() != 1 {
+		t.Fatalf("cached lookup: err = %v, requests = %d", err, calls.Load())
+	}
+}
+
+func TestFetchNegativeTTL(t *testing.T) {
+	var calls atomic.Int32
+
+	s := NewWithClient(&fakeClient{
+		user: func(id valour.UserID) (*valour.User, error) {
+			calls.Add(1)
+			return nil, &valour.StatusError{StatusCode: http.StatusNotFound}
+		},
+	}, WithNegativeTTL(20*time.Millisecond))
+
+	for range 3 {
+		if _, err := s.User(1); !valour.IsNotFound(err) {
+			t.Fatalf("err = %v, want not found", err)
+		}
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("requests = %d, want 1 while the not found response is remembered", n)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := s.User(1); !valour.IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("requests = %d, want 2 after the TTL expired", n)
+	}
+}
+
+func TestNegativeCacheSweepsExpired(t *testing.T) {
+	c := newNegativeCache()
+	notFound := &valour.StatusError{StatusCode: http.StatusNotFound}
+
+	c.set("a", notFound, 10*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Setting another key removes "a", even though it's never looked up again
+	c.set("b", notFound, 10*time.Millisecond)
+
+	if c.entries.Has("a") {
+		t.Fatal("expected the expired entry to be swept")
+	}
+
+	if !c.entries.Has("b") {
+		t.Fatal("expected the new entry to be kept")
+	}
+}
@@ -1,16 +1,34 @@
 package state
 
 import (
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/auroradevllc/handler"
 	valour "github.com/auroradevllc/valourgo"
 	"github.com/auroradevllc/valourgo/state/store"
 	"github.com/auroradevllc/valourgo/state/store/defaultstore"
+	"golang.org/x/sync/singleflight"
 )
 
 type State struct {
 	valour.Client
 	*store.Cabinet
 	*handler.Handler
+
+	fetches     singleflight.Group
+	negative    *negativeCache
+	negativeTTL time.Duration
+	readThrough bool
+
+	prefetchMembers bool
+
+	// reactions serializes updates to cached messages' reactions
+	reactions sync.Mutex
 }
 
 var _ valour.Client = (*State)(nil)
@@ -25,134 +43,290 @@ func New(token string, opts ...valour.Option) (valour.Client, error) {
 	return NewWithClient(c), nil
 }
 
-func NewWithClient(c valour.Client) *State {
-	s := &State{
-		Client:  c,
-		Cabinet: defaultstore.New(),
-		Handler: handler.New(),
+type Option func(s *State)
+
+// WithCabinet sets the store used by the state, defaulting to defaultstore.New
+func WithCabinet(c *store.Cabinet) Option {
+	return func(s *State) {
+		s.Cabinet = c
 	}
+}
 
-	s.hookEvents()
+// WithoutReadThrough disables requesting missing items from the API.
+// Lookups only return what is already in the store, failing with store.ErrNotFound otherwise.
+func WithoutReadThrough() Option {
+	return func(s *State) {
+		s.readThrough = false
+	}
+}
 
-	return s
+// WithNegativeTTL sets how long not found responses from the API are remembered.
+// A TTL of 0 disables this.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(s *State) {
+		s.negativeTTL = ttl
+	}
 }
 
-func (s *State) Me() (*valour.User, error) {
-	me, err := s.Cabinet.Me()
+// WithMemberPrefetch requests a planet's full member list when it's joined, so MemberStore.Members
+// returns every member rather than only those seen through events.
+func WithMemberPrefetch() Option {
+	return func(s *State) {
+		s.prefetchMembers = true
+	}
+}
 
-	if err == nil {
-		return me, nil
+func NewWithClient(c valour.Client, opts ...Option) *State {
+	s := &State{
+		Client:      c,
+		Handler:     handler.New(),
+		negative:    newNegativeCache(),
+		negativeTTL: defaultNegativeTTL,
+		readThrough: true,
 	}
 
-	me, err = s.Client.Me()
+	for _, opt := range opts {
+		opt(s)
+	}
 
-	if err == nil {
-		s.Cabinet.MyselfSet(*me, false)
+	if s.Cabinet == nil {
+		s.Cabinet = defaultstore.New()
 	}
 
-	return me, err
+	s.hookEvents()
+
+	return s
+}
+
+func (s *State) Me() (*valour.User, error) {
+	return fetch(s, "me", s.Cabinet.Me, s.Client.Me, func(me *valour.User) {
+		_ = s.Cabinet.MyselfSet(*me, false)
+	})
+}
+
+func (s *State) User(id valour.UserID) (*valour.User, error) {
+	return fetch(s, "user:"+id.String(),
+		func() (*valour.User, error) {
+			return s.Cabinet.User(id)
+		},
+		func() (*valour.User, error) {
+			return s.Client.User(id)
+		},
+		func(user *valour.User) {
+			_ = s.Cabinet.UserSet(user, false)
+		})
 }
 
 func (s *State) Planet(id valour.PlanetID) (*valour.Planet, error) {
-	p, err := s.Cabinet.Planet(id)
+	return fetch(s, "planet:"+id.String(),
+		func() (*valour.Planet, error) {
+			return s.Cabinet.Planet(id)
+		},
+		func() (*valour.Planet, error) {
+			return s.Client.Planet(id)
+		},
+		func(p *valour.Planet) {
+			_ = s.Cabinet.PlanetSet(p, false)
+		})
+}
 
-	if err == nil {
-		return p, nil
-	}
+func (s *State) Planets() ([]valour.Planet, error) {
+	return fetch(s, "planets",
+		func() ([]valour.Planet, error) {
+			planets, err := s.Cabinet.Planets()
+
+			// The store may only contain planets seen through events, an empty list is treated as a miss
+			if err == nil && len(planets) == 0 {
+				return nil, store.ErrNotFound
+			}
+
+			return planets, err
+		},
+		s.Client.Planets,
+		func(planets []valour.Planet) {
+			for i := range planets {
+				_ = s.Cabinet.PlanetSet(&planets[i], false)
+			}
+		})
+}
 
-	p, err = s.Client.Planet(id)
+func (s *State) Channel(planetID valour.PlanetID, channelID valour.ChannelID) (*valour.Channel, error) {
+	return fetch(s, "channel:"+channelID.String(),
+		func() (*valour.Channel, error) {
+			return s.Cabinet.Channel(channelID)
+		},
+		func() (*valour.Channel, error) {
+			return s.Client.Channel(planetID, channelID)
+		},
+		func(channel *valour.Channel) {
+			_ = s.Cabinet.ChannelSet(channel, false)
+		})
+}
 
-	if err == nil {
-		s.Cabinet.PlanetSet(p, false)
-	}
+func (s *State) Channels(id valour.PlanetID) ([]valour.Channel, error) {
+	return fetch(s, "channels:"+id.String(),
+		func() ([]valour.Channel, error) {
+			return s.Cabinet.Channels(id)
+		},
+		func() ([]valour.Channel, error) {
+			return s.Client.Channels(id)
+		},
+		func(channels []valour.Channel) {
+			for i := range channels {
+				_ = s.Cabinet.ChannelSet(&channels[i], false)
+			}
+		})
+}
 
-	return p, err
+func (s *State) Role(planetID valour.PlanetID, roleID valour.RoleID) (*valour.Role, error) {
+	return fetch(s, "role:"+planetID.String()+":"+roleID.String(),
+		func() (*valour.Role, error) {
+			return s.Cabinet.Role(planetID, roleID)
+		},
+		func() (*valour.Role, error) {
+			return s.Client.Role(planetID, roleID)
+		},
+		func(role *valour.Role) {
+			_ = s.Cabinet.RoleSet(role, false)
+		})
 }
 
-func (s *State) Planets() ([]valour.Planet, error) {
-	planets, err := s.Cabinet.Planets()
+func (s *State) Roles(planetID valour.PlanetID) ([]valour.Role, error) {
+	return fetch(s, "roles:"+planetID.String(),
+		func() ([]valour.Role, error) {
+			return s.Cabinet.Roles(planetID)
+		},
+		func() ([]valour.Role, error) {
+			return s.Client.Roles(planetID)
+		},
+		func(roles []valour.Role) {
+			for i := range roles {
+				_ = s.Cabinet.RoleSet(&roles[i], false)
+			}
+		})
+}
 
-	if err == nil {
-		return planets, nil
-	}
+func (s *State) PermissionsNode(planetID valour.PlanetID, id valour.PermissionsNodeID) (*valour.PermissionsNode, error) {
+	return fetch(s, "permissionsNode:"+planetID.String()+":"+id.String(),
+		func() (*valour.PermissionsNode, error) {
+			return s.Cabinet.PermissionsNode(planetID, id)
+		},
+		func() (*valour.PermissionsNode, error) {
+			return s.Client.PermissionsNode(planetID, id)
+		},
+		func(node *valour.PermissionsNode) {
+			_ = s.Cabinet.PermissionsNodeSet(node, false)
+		})
+}
 
-	planets, err = s.Client.Planets()
+func (s *State) PermissionsNodes(planetID valour.PlanetID) ([]valour.PermissionsNode, error) {
+	return fetch(s, "permissionsNodes:"+planetID.String(),
+		func() ([]valour.PermissionsNode, error) {
+			return s.Cabinet.PermissionsNodes(planetID)
+		},
+		func() ([]valour.PermissionsNode, error) {
+			return s.Client.PermissionsNodes(planetID)
+		},
+		func(nodes []valour.PermissionsNode) {
+			for i := range nodes {
+				_ = s.Cabinet.PermissionsNodeSet(&nodes[i], false)
+			}
+		})
+}
 
-	if err == nil {
-		for i := range planets {
-			s.Cabinet.PlanetSet(&planets[i], false)
-		}
-	}
+func (s *State) Emoji(planetID valour.PlanetID, emojiID valour.EmojiID) (*valour.Emoji, error) {
+	return fetch(s, "emoji:"+planetID.String()+":"+emojiID.String(),
+		func() (*valour.Emoji, error) {
+			return s.Cabinet.Emoji(planetID, emojiID)
+		},
+		func() (*valour.Emoji, error) {
+			return s.Client.Emoji(planetID, emojiID)
+		},
+		func(emoji *valour.Emoji) {
+			_ = s.Cabinet.EmojiAdd(emoji, false)
+		})
+}
 
-	return planets, err
+func (s *State) Emojis(planetID valour.PlanetID) ([]valour.Emoji, error) {
+	return fetch(s, "emojis:"+planetID.String(),
+		func() ([]valour.Emoji, error) {
+			return s.Cabinet.Emojis(planetID)
+		},
+		func() ([]valour.Emoji, error) {
+			return s.Client.Emojis(planetID)
+		},
+		func(emojis []valour.Emoji) {
+			_ = s.Cabinet.EmojiSet(planetID, emojis, false)
+		})
 }
 
-func (s *State) Channel(planetID valour.PlanetID, channelID valour.ChannelID) (*valour.Channel, error) {
-	channel, err := s.Cabinet.Channel(channelID)
+// CreateEmoji creates a custom emoji and stores it
+func (s *State) CreateEmoji(planetID valour.PlanetID, name, fileName string, r io.Reader, size int64) (*valour.Emoji, error) {
+	emoji, err := s.Client.CreateEmoji(planetID, name, fileName, r, size)
 
-	if err == nil {
-		return channel, nil
+	if err != nil {
+		return nil, err
 	}
 
-	channel, err = s.Client.Channel(planetID, channelID)
+	_ = s.Cabinet.EmojiAdd(emoji, true)
+
+	return emoji, nil
+}
 
-	if err == nil {
-		_ = s.Cabinet.ChannelSet(channel, false)
+// DeleteEmoji deletes a custom emoji and removes it from the store
+func (s *State) DeleteEmoji(planetID valour.PlanetID, emojiID valour.EmojiID) error {
+	if err := s.Client.DeleteEmoji(planetID, emojiID); err != nil {
+		return err
 	}
 
-	return channel, err
+	_ = s.Cabinet.EmojiRemove(planetID, emojiID)
+
+	return nil
 }
 
-func (s *State) Channels(id valour.PlanetID) ([]valour.Channel, error) {
-	channels, err := s.Cabinet.Channels(id)
+// ChannelPermissions calculates a member's permissions in a channel from the cached roles and permissions nodes.
+// See valour.ChannelPermissions for how permissions are combined.
+func (s *State) ChannelPermissions(memberID valour.MemberID, channelID valour.ChannelID) (uint64, error) {
+	member, err := s.Member(memberID)
 
-	if err == nil {
-		return channels, nil
+	if err != nil {
+		return 0, err
 	}
 
-	channels, err = s.Client.Channels(id)
+	planet, err := s.Planet(member.PlanetID)
 
-	if err == nil {
-		for i := range channels {
-			_ = s.Cabinet.ChannelSet(&channels[i], false)
-		}
+	if err != nil {
+		return 0, err
 	}
 
-	return channels, err
-}
+	channels, err := s.Channels(member.PlanetID)
 
-func (s *State) Role(planetID valour.PlanetID, roleID valour.RoleID) (*valour.Role, error) {
-	role, err := s.Cabinet.Role(planetID, roleID)
-
-	if err == nil {
-		return role, nil
+	if err != nil {
+		return 0, err
 	}
 
-	role, err = s.Client.Role(planetID, roleID)
+	idx := slices.IndexFunc(channels, func(c valour.Channel) bool {
+		return c.ID == channelID
+	})
 
-	if err == nil {
-		_ = s.Cabinet.RoleSet(role, false)
+	if idx == -1 {
+		return 0, store.ErrNotFound
 	}
 
-	return role, err
-}
-
-func (s *State) Roles(planetID valour.PlanetID) ([]valour.Role, error) {
-	roles, err := s.Cabinet.Roles(planetID)
+	roles, err := s.Roles(member.PlanetID)
 
-	if err == nil {
-		return roles, nil
+	if err != nil {
+		return 0, err
 	}
 
-	roles, err = s.Client.Roles(planetID)
+	nodes, err := s.PermissionsNodes(member.PlanetID)
 
-	if err == nil {
-		for i := range roles {
-			_ = s.Cabinet.RoleSet(&roles[i], false)
-		}
+	// A planet without any nodes is valid, roles then only use their base permissions
+	if err != nil && !valour.IsNotFound(err) && !errors.Is(err, store.ErrNotFound) {
+		return 0, err
 	}
 
-	return roles, err
+	return valour.ChannelPermissions(*planet, *member, channels[idx], channels, roles, nodes), nil
 }
 
 func (s *State) MyMember(planetID valour.PlanetID) (*valour.Member, error) {
@@ -172,33 +346,144 @@ func (s *State) MyMember(planetID valour.PlanetID) (*valour.Member, error) {
 }
 
 func (s *State) Member(id valour.MemberID) (*valour.Member, error) {
-	member, err := s.Cabinet.Member(id)
+	return fetch(s, "member:"+id.String(),
+		func() (*valour.Member, error) {
+			return s.Cabinet.Member(id)
+		},
+		func() (*valour.Member, error) {
+			return s.Client.Member(id)
+		},
+		s.setMember)
+}
+
+func (s *State) MemberByUser(planetID valour.PlanetID, id valour.UserID) (*valour.Member, error) {
+	return fetch(s, "memberByUser:"+planetID.String()+":"+id.String(),
+		func() (*valour.Member, error) {
+			return s.Cabinet.MemberByUser(planetID, id)
+		},
+		func() (*valour.Member, error) {
+			return s.Client.MemberByUser(planetID, id)
+		},
+		s.setMember)
+}
+
+// PlanetMembers always retrieves members from the API, storing them as they're received
+func (s *State) PlanetMembers(planetID valour.PlanetID) ([]valour.Member, error) {
+	var members []valour.Member
+
+	for member, err := range s.PlanetMembersIter(planetID) {
+		if err != nil {
+			return members, err
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// PlanetMembersIter retrieves members from the API, storing them as they're received.
+// Once every member has been received, the store is told it holds the planet's full member list.
+func (s *State) PlanetMembersIter(planetID valour.PlanetID) iter.Seq2[valour.Member, error] {
+	return func(yield func(valour.Member, error) bool) {
+		var (
+			memberIDs []valour.MemberID
+			failed    bool
+		)
+
+		for member, err := range s.Client.PlanetMembersIter(planetID) {
+			if err == nil {
+				_ = s.Cabinet.MemberSet(&member, true)
+
+				if member.User.ID.IsValid() {
+					_ = s.Cabinet.UserSet(&member.User, true)
+				}
+
+				memberIDs = append(memberIDs, member.ID)
+			} else {
+				failed = true
+			}
+
+			if !yield(member, err) {
+				return
+			}
+		}
 
-	if err == nil {
-		return member, nil
+		if failed {
+			return
+		}
+
+		if err := s.Cabinet.MembersComplete(planetID, memberIDs); err != nil {
+			s.logError(err)
+		}
 	}
+}
 
-	member, err = s.Client.Member(id)
+// MemberRoles returns the roles a member holds, ordered by position.
+// Role membership is kept current by PlanetMember-Update events.
+func (s *State) MemberRoles(id valour.MemberID) ([]valour.Role, error) {
+	member, err := s.Member(id)
 
-	if err == nil {
-		s.Cabinet.MemberSet(member, false)
+	if err != nil {
+		return nil, err
 	}
 
-	return member, err
+	roles, err := s.Roles(member.PlanetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return member.Roles(roles), nil
 }
 
-func (s *State) MemberByUser(planetID valour.PlanetID, id valour.UserID) (*valour.Member, error) {
-	member, err := s.Cabinet.MemberByUser(planetID, id)
+// setMember stores a member retrieved from the API
+func (s *State) setMember(member *valour.Member) {
+	_ = s.Cabinet.MemberSet(member, false)
 
-	if err == nil {
-		return member, nil
+	if member.User.ID.IsValid() {
+		_ = s.Cabinet.UserSet(&member.User, false)
 	}
+}
+
+func (s *State) Message(id valour.MessageID) (*valour.Message, error) {
+	return fetch(s, "message:"+id.String(),
+		func() (*valour.Message, error) {
+			return s.Cabinet.Message(id)
+		},
+		func() (*valour.Message, error) {
+			return s.Client.Message(id)
+		},
+		func(message *valour.Message) {
+			_ = s.Cabinet.MessageSet(message, false)
+		})
+}
+
+// Reactions returns a message's reactions aggregated by emoji.
+// Counts are kept current by reaction events while the message is cached.
+func (s *State) Reactions(id valour.MessageID) ([]valour.ReactionSummary, error) {
+	msg, err := s.Message(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var me valour.UserID
+
+	if user, err := s.Me(); err == nil {
+		me = user.ID
+	}
+
+	return msg.ReactionSummaries(me), nil
+}
 
-	member, err = s.Client.MemberByUser(planetID, id)
+// Messages always retrieves messages from the API, as the store only holds messages we've seen
+func (s *State) Messages(planetID valour.PlanetID, channelID valour.ChannelID, limit uint) ([]valour.Message, error) {
+	messages, err := s.Client.Messages(planetID, channelID, limit)
 
-	if err == nil {
-		s.Cabinet.MemberSet(member, false)
+	for i := range messages {
+		_ = s.Cabinet.MessageSet(&messages[i], false)
 	}
 
-	return member, err
+	return messages, err
 }
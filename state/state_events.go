@@ -1,6 +1,14 @@
 package state
 
-import valour "github.com/auroradevllc/valourgo"
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/auroradevllc/handler"
+	valour "github.com/auroradevllc/valourgo"
+)
 
 func (s *State) hookEvents() {
 	s.Client.AddSyncHandler(func(event interface{}) {
@@ -14,7 +22,14 @@ func (s *State) hookEvents() {
 func (s *State) onEvent(e interface{}) {
 	switch ev := e.(type) {
 	case *valour.PlanetJoinEvent:
-		s.retrieveInitialPlanet(ev.PlanetID)
+		if err := s.retrieveInitialPlanet(ev.PlanetID); err != nil {
+			s.logError(err)
+		}
+
+		if s.prefetchMembers {
+			// Large planets can take many requests, so don't hold up other events
+			go s.prefetchPlanetMembers(ev.PlanetID)
+		}
 	case *valour.PlanetUpdateEvent:
 		if err := s.Cabinet.PlanetSet(&ev.Planet, true); err != nil {
 			s.logError(err)
@@ -23,6 +38,10 @@ func (s *State) onEvent(e interface{}) {
 		if err := s.Cabinet.PlanetRemove(ev.PlanetID); err != nil {
 			s.logError(err)
 		}
+
+		if err := s.Cabinet.PlanetLeave(ev.PlanetID); err != nil {
+			s.logError(err)
+		}
 	case *valour.ChannelUpdateEvent:
 		if err := s.Cabinet.ChannelSet(&ev.Channel, true); err != nil {
 			s.logError(err)
@@ -31,6 +50,131 @@ func (s *State) onEvent(e interface{}) {
 		if err := s.Cabinet.ChannelRemove(&ev.Channel); err != nil {
 			s.logError(err)
 		}
+	case *valour.RoleUpdateEvent:
+		if err := s.Cabinet.RoleSet(&ev.Role, true); err != nil {
+			s.logError(err)
+		}
+	case *valour.RoleDeleteEvent:
+		if err := s.Cabinet.RoleRemove(ev.PlanetID, ev.ID); err != nil {
+			s.logError(err)
+		}
+	case *valour.PermissionsNodeUpdateEvent:
+		if err := s.Cabinet.PermissionsNodeSet(&ev.PermissionsNode, true); err != nil {
+			s.logError(err)
+		}
+	case *valour.PermissionsNodeDeleteEvent:
+		if err := s.Cabinet.PermissionsNodeRemove(ev.PlanetID, ev.ID); err != nil {
+			s.logError(err)
+		}
+	case *valour.EmojiUpdateEvent:
+		if err := s.Cabinet.EmojiAdd(&ev.Emoji, true); err != nil {
+			s.logError(err)
+		}
+	case *valour.EmojiDeleteEvent:
+		if err := s.Cabinet.EmojiRemove(ev.PlanetID, ev.ID); err != nil {
+			s.logError(err)
+		}
+	case *valour.MessageCreateEvent:
+		if err := s.Cabinet.MessageSet(&ev.Message, true); err != nil {
+			s.logError(err)
+		}
+	case *valour.MessageEditEvent:
+		if err := s.Cabinet.MessageSet(&ev.Message, true); err != nil {
+			s.logError(err)
+		}
+	case *valour.MessageDeleteEvent:
+		if err := s.Cabinet.MessageRemove(ev.ID); err != nil {
+			s.logError(err)
+		}
+	case *valour.MessageReactionAddedEvent:
+		s.updateReactions(ev.MessageID, func(reactions []valour.Reaction) []valour.Reaction {
+			// The event may repeat a reaction the message was already cached with
+			if slices.ContainsFunc(reactions, func(r valour.Reaction) bool {
+				return r.AuthorUserID == ev.UserID && r.Emoji == ev.Emoji
+			}) {
+				return reactions
+			}
+
+			return append(reactions, valour.Reaction{
+				Emoji:          ev.Emoji,
+				MessageID:      ev.MessageID,
+				AuthorUserID:   ev.UserID,
+				AuthorMemberID: ev.MemberID,
+				CreatedAt:      time.Now().UTC(),
+			})
+		})
+	case *valour.MessageReactionRemovedEvent:
+		s.updateReactions(ev.MessageID, func(reactions []valour.Reaction) []valour.Reaction {
+			return slices.DeleteFunc(reactions, func(r valour.Reaction) bool {
+				return r.AuthorUserID == ev.UserID && r.Emoji == ev.Emoji
+			})
+		})
+	case *valour.UserUpdateEvent:
+		if err := s.Cabinet.UserSet(&ev.User, true); err != nil {
+			s.logError(err)
+		}
+	case *valour.PlanetMemberUpdate:
+		if err := s.Cabinet.MemberSet(&ev.Member, true); err != nil {
+			s.logError(err)
+		}
+
+		if ev.User.ID.IsValid() {
+			if err := s.Cabinet.UserSet(&ev.User, true); err != nil {
+				s.logError(err)
+			}
+		}
+	case *valour.PlanetBanUpdate:
+		s.removeBanned(&ev.Ban)
+	case *valour.PlanetMemberDelete:
+		if err := s.Cabinet.MemberRemove(ev.ID); err != nil {
+			s.logError(err)
+		}
+
+		// Our own member being removed means we've left the planet
+		if me, err := s.Cabinet.Me(); err == nil && me.ID == ev.UserID {
+			if err := s.Cabinet.PlanetLeave(ev.PlanetID); err != nil {
+				s.logError(err)
+			}
+		}
+	}
+}
+
+// updateReactions changes the reactions of a cached message, if it's cached
+func (s *State) updateReactions(id valour.MessageID, update func(reactions []valour.Reaction) []valour.Reaction) {
+	s.reactions.Lock()
+	defer s.reactions.Unlock()
+
+	msg, err := s.Cabinet.Message(id)
+
+	if err != nil {
+		return
+	}
+
+	// Copy the reactions so the update doesn't change a slice shared with other copies of the message
+	msg.Reactions = update(slices.Clone(msg.Reactions))
+
+	if err := s.Cabinet.MessageSet(msg, true); err != nil {
+		s.logError(err)
+	}
+}
+
+// removeBanned removes a banned user's member from the store
+func (s *State) removeBanned(ban *valour.Ban) {
+	if ban.Expired() {
+		return
+	}
+
+	if member, err := s.Cabinet.MemberByUser(ban.PlanetID, ban.TargetID); err == nil {
+		if err := s.Cabinet.MemberRemove(member.ID); err != nil {
+			s.logError(err)
+		}
+	}
+
+	// Being banned ourselves means we've left the planet
+	if me, err := s.Cabinet.Me(); err == nil && me.ID == ban.TargetID {
+		if err := s.Cabinet.PlanetLeave(ban.PlanetID); err != nil {
+			s.logError(err)
+		}
 	}
 }
 
@@ -52,12 +196,62 @@ func (s *State) retrieveInitialPlanet(id valour.PlanetID) error {
 		}
 	}
 
-	// handle data.Roles
+	for _, role := range data.Roles {
+		if err := s.Cabinet.RoleSet(&role, false); err != nil {
+			s.logError(err)
+		}
+	}
+
+	for _, node := range data.PermissionsNodes {
+		if err := s.Cabinet.PermissionsNodeSet(&node, false); err != nil {
+			s.logError(err)
+		}
+	}
+
+	if err := s.Cabinet.EmojiSet(id, data.Emojis, true); err != nil {
+		s.logError(err)
+	}
 
-	// handle data.Emojis
 	return nil
 }
 
+// prefetchPlanetMembers stores every member of a planet
+func (s *State) prefetchPlanetMembers(id valour.PlanetID) {
+	for _, err := range s.PlanetMembersIter(id) {
+		if err != nil {
+			s.logError(err)
+			return
+		}
+	}
+}
+
 func (s *State) logError(err error) {
 	// TODO: log error
 }
+
+// The methods below resolve the handler methods of State to its own handler,
+// which is only called once the store has been updated.
+
+func (s *State) Call(ev interface{}) {
+	s.Handler.Call(ev)
+}
+
+func (s *State) AllCallersForType(t reflect.Type) func(yield func(handler.Caller) bool) {
+	return s.Handler.AllCallersForType(t)
+}
+
+func (s *State) WaitFor(ctx context.Context, fn func(interface{}) bool) interface{} {
+	return s.Handler.WaitFor(ctx, fn)
+}
+
+func (s *State) ChanFor(fn func(interface{}) bool) (out <-chan interface{}, cancel func()) {
+	return s.Handler.ChanFor(fn)
+}
+
+func (s *State) AddHandler(fn interface{}) (rm func()) {
+	return s.Handler.AddHandler(fn)
+}
+
+func (s *State) AddSyncHandler(fn interface{}) (rm func()) {
+	return s.Handler.AddSyncHandler(fn)
+}
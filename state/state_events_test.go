@@ -0,0 +1,53 @@
+package state
+
+import (
+	"testing"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
+func TestReactionAddedIgnoresDuplicates(t *testing.T) {
+	s := NewWithClient(&fakeClient{})
+
+	msg := &valour.Message{
+		ID:        1,
+		Reactions: []valour.Reaction{{MessageID: 1, AuthorUserID: 10, Emoji: "👍"}},
+	}
+
+	if err := s.Cabinet.MessageSet(msg, false); err != nil {
+		t.Fatal(err)
+	}
+
+	reaction := func(user valour.UserID, emoji string) *valour.MessageReactionAddedEvent {
+		return &valour.MessageReactionAddedEvent{MessageReactionEvent: valour.MessageReactionEvent{
+			MessageID: 1,
+			UserID:    user,
+			Emoji:     emoji,
+		}}
+	}
+
+	s.onEvent(reaction(10, "👍"))
+	s.onEvent(reaction(11, "👍"))
+	s.onEvent(reaction(11, "👍"))
+	s.onEvent(reaction(10, "🎉"))
+
+	cached, err := s.Cabinet.Message(1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cached.Reactions) != 3 {
+		t.Fatalf("cached %d reactions, want 3 distinct reactions: %+v", len(cached.Reactions), cached.Reactions)
+	}
+
+	s.onEvent(&valour.MessageReactionRemovedEvent{MessageReactionEvent: valour.MessageReactionEvent{
+		MessageID: 1,
+		UserID:    11,
+		Emoji:     "👍",
+	}})
+
+	if cached, _ := s.Cabinet.Message(1); len(cached.Reactions) != 2 {
+		t.Fatalf("cached %d reactions after removing one, want 2", len(cached.Reactions))
+	}
+}
@@ -0,0 +1,38 @@
+package state
+
+import (
+	"testing"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store/defaultstore"
+)
+
+func TestPlanetMembersCompletesEvictedPlanet(t *testing.T) {
+	client := &fakeClient{
+		members: []valour.Member{
+			{ID: 1, UserID: 1, PlanetID: 1},
+			{ID: 2, UserID: 2, PlanetID: 1},
+		},
+	}
+
+	s := NewWithClient(client, WithCabinet(defaultstore.New(defaultstore.WithMemberPolicy(defaultstore.Policy{MaxEntries: 2}))))
+
+	if _, err := s.PlanetMembers(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Storing a member of another planet evicts one of the first planet's members
+	_ = s.Cabinet.MemberSet(&valour.Member{ID: 3, UserID: 3, PlanetID: 2}, true)
+
+	if _, err := s.Cabinet.Members(1); err == nil {
+		t.Fatal("expected Members to miss after an eviction")
+	}
+
+	if _, err := s.PlanetMembers(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if members, err := s.Cabinet.Members(1); err != nil || len(members) != 2 {
+		t.Fatalf("Members = %v, %v, want both members after refetching", members, err)
+	}
+}
@@ -1,5 +1,11 @@
 package store
 
+import (
+	"errors"
+
+	valour "github.com/auroradevllc/valourgo"
+)
+
 type Cabinet struct {
 	MeStore
 	ChannelStore
@@ -7,17 +13,92 @@ type Cabinet struct {
 	MemberStore
 	RoleStore
 	EmojiStore
+	UserStore
+	MessageStore
+	PermissionsNodeStore
+}
+
+// CabinetStats contains the Stats of every store in a Cabinet.
+// Stores that don't implement StatsReporter report zero values.
+type CabinetStats struct {
+	Me      Stats
+	Channel Stats
+	Planet  Stats
+	Member  Stats
+	Role    Stats
+	Emoji   Stats
+	User    Stats
+	Message Stats
+
+	PermissionsNode Stats
 }
 
 func (c *Cabinet) Reset() error {
-	errs := []error{
-		c.MeStore.Reset(),
-		c.ChannelStore.Reset(),
-		c.PlanetStore.Reset(),
-		c.MemberStore.Reset(),
-		c.RoleStore.Reset(),
-		c.EmojiStore.Reset(),
+	var errs []error
+
+	for _, s := range c.stores() {
+		errs = append(errs, s.Reset())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Stats collects usage statistics from all stores
+func (c *Cabinet) Stats() CabinetStats {
+	return CabinetStats{
+		Me:      statsOf(c.MeStore),
+		Channel: statsOf(c.ChannelStore),
+		Planet:  statsOf(c.PlanetStore),
+		Member:  statsOf(c.MemberStore),
+		Role:    statsOf(c.RoleStore),
+		Emoji:   statsOf(c.EmojiStore),
+		User:    statsOf(c.UserStore),
+		Message: statsOf(c.MessageStore),
+
+		PermissionsNode: statsOf(c.PermissionsNodeStore),
+	}
+}
+
+// PlanetLeave notifies every store implementing PlanetLeaver that the client has left a planet
+func (c *Cabinet) PlanetLeave(id valour.PlanetID) error {
+	var errs []error
+
+	for _, s := range c.stores() {
+		if l, ok := s.(PlanetLeaver); ok {
+			errs = append(errs, l.PlanetLeave(id))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MembersComplete notifies the member store, if it implements MembersCompleter, that all of a planet's members were stored
+func (c *Cabinet) MembersComplete(id valour.PlanetID, memberIDs []valour.MemberID) error {
+	if mc, ok := c.MemberStore.(MembersCompleter); ok {
+		return mc.MembersComplete(id, memberIDs)
+	}
+
+	return nil
+}
+
+func (c *Cabinet) stores() []Resettable {
+	return []Resettable{
+		c.MeStore,
+		c.ChannelStore,
+		c.PlanetStore,
+		c.MemberStore,
+		c.RoleStore,
+		c.EmojiStore,
+		c.UserStore,
+		c.MessageStore,
+		c.PermissionsNodeStore,
+	}
+}
+
+func statsOf(s any) Stats {
+	if r, ok := s.(StatsReporter); ok {
+		return r.Stats()
 	}
 
-	return errs[0]
+	return Stats{}
 }
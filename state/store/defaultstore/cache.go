@@ -0,0 +1,267 @@
+package defaultstore
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+// Policy controls how many entries a store keeps, and for how long
+type Policy struct {
+	// MaxEntries is the maximum number of entries kept, evicting the least recently used first.
+	// Zero means unlimited.
+	MaxEntries int
+
+	// TTL is how long an entry is kept after it was last set.
+	// Expired entries are removed lazily, as the store is used. Zero means entries never expire.
+	TTL time.Duration
+}
+
+// counter tracks lookup statistics for a store
+type counter struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// record counts a lookup as a hit or miss, returning ok for convenience
+func (c *counter) record(ok bool) bool {
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+
+	return ok
+}
+
+func (c *counter) stats(entries int) store.Stats {
+	return store.Stats{
+		Entries:   entries,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+type cacheEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// cache is a concurrency safe LRU cache with optional expiry
+type cache[K comparable, V any] struct {
+	counter
+
+	mut    sync.Mutex
+	policy Policy
+	items  map[K]*list.Element
+	order  *list.List
+
+	// onEvict is called for entries removed by the policy, once the lock has been released
+	onEvict func(K, V)
+	evicted []*cacheEntry[K, V]
+}
+
+func newCache[K comparable, V any](policy Policy, onEvict func(K, V)) *cache[K, V] {
+	return &cache[K, V]{
+		policy:  policy,
+		items:   make(map[K]*list.Element),
+		order:   list.New(),
+		onEvict: onEvict,
+	}
+}
+
+// Get returns an entry, marking it as recently used
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.get(key, true)
+
+	c.record(ok)
+
+	return v, ok
+}
+
+// Contains checks whether an entry is stored, without expiring it or affecting its position or the statistics.
+// Unlike the other lookups it never calls onEvict, so it's safe to call while holding locks onEvict takes.
+func (c *cache[K, V]) Contains(key K) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	_, ok := c.items[key]
+
+	return ok
+}
+
+// Peek returns an entry without affecting its position or the statistics
+func (c *cache[K, V]) Peek(key K) (V, bool) {
+	return c.get(key, false)
+}
+
+func (c *cache[K, V]) get(key K, touch bool) (V, bool) {
+	c.mut.Lock()
+	defer c.unlock()
+
+	el, ok := c.items[key]
+
+	if !ok || c.evictIfExpired(el) {
+		var zero V
+		return zero, false
+	}
+
+	if touch {
+		c.order.MoveToFront(el)
+	}
+
+	return el.Value.(*cacheEntry[K, V]).value, true
+}
+
+// Set stores an entry, only replacing an existing one if update is true.
+// It returns whether the value was stored.
+func (c *cache[K, V]) Set(key K, value V, update bool) bool {
+	c.mut.Lock()
+	defer c.unlock()
+
+	var expires time.Time
+
+	if c.policy.TTL > 0 {
+		expires = time.Now().Add(c.policy.TTL)
+	}
+
+	if el, ok := c.items[key]; ok && !c.evictIfExpired(el) {
+		if !update {
+			return false
+		}
+
+		e := el.Value.(*cacheEntry[K, V])
+		e.value = value
+		e.expires = expires
+
+		c.order.MoveToFront(el)
+
+		return true
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry[K, V]{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+
+	c.prune()
+
+	return true
+}
+
+// Remove deletes an entry, returning the removed value
+func (c *cache[K, V]) Remove(key K) (V, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	el, ok := c.items[key]
+
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.remove(el)
+
+	return el.Value.(*cacheEntry[K, V]).value, true
+}
+
+// RemoveFunc deletes all entries that fn returns true for
+func (c *cache[K, V]) RemoveFunc(fn func(K, V) bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		e := el.Value.(*cacheEntry[K, V])
+
+		if fn(e.key, e.value) {
+			c.remove(el)
+		}
+
+		el = next
+	}
+}
+
+// Len returns the number of entries, which may include expired entries not yet removed
+func (c *cache[K, V]) Len() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return len(c.items)
+}
+
+// Clear removes all entries
+func (c *cache[K, V]) Clear() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+	c.evicted = nil
+}
+
+func (c *cache[K, V]) Stats() store.Stats {
+	return c.stats(c.Len())
+}
+
+// evictIfExpired evicts an entry past its TTL, returning whether it was evicted
+func (c *cache[K, V]) evictIfExpired(el *list.Element) bool {
+	e := el.Value.(*cacheEntry[K, V])
+
+	if e.expires.IsZero() || time.Now().Before(e.expires) {
+		return false
+	}
+
+	c.evict(el)
+
+	return true
+}
+
+// prune evicts entries over the size limit, along with expired entries at the back of the list
+func (c *cache[K, V]) prune() {
+	for c.policy.MaxEntries > 0 && c.order.Len() > c.policy.MaxEntries {
+		c.evict(c.order.Back())
+	}
+
+	for el := c.order.Back(); el != nil; el = c.order.Back() {
+		if !c.evictIfExpired(el) {
+			break
+		}
+	}
+}
+
+// evict removes an entry for the policy, queueing it for onEvict
+func (c *cache[K, V]) evict(el *list.Element) {
+	c.remove(el)
+	c.evictions.Add(1)
+
+	if c.onEvict != nil {
+		c.evicted = append(c.evicted, el.Value.(*cacheEntry[K, V]))
+	}
+}
+
+// unlock releases the lock, then calls onEvict for entries evicted while it was held,
+// so onEvict may safely call back into the cache
+func (c *cache[K, V]) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+
+	c.mut.Unlock()
+
+	for _, e := range evicted {
+		c.onEvict(e.key, e.value)
+	}
+}
+
+func (c *cache[K, V]) remove(el *list.Element) {
+	delete(c.items, el.Value.(*cacheEntry[K, V]).key)
+	c.order.Remove(el)
+}
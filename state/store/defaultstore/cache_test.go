@@ -0,0 +1,270 @@
+package defaultstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var evicted []int
+
+	c := newCache(Policy{MaxEntries: 2}, func(k int, _ string) {
+		evicted = append(evicted, k)
+	})
+
+	c.Set(1, "one", false)
+	c.Set(2, "two", false)
+
+	// Touch 1 so 2 is the least recently used
+	if _, ok := c.Get(1); !ok {
+		t.Fatal("expected 1 to be stored")
+	}
+
+	c.Set(3, "three", false)
+
+	if _, ok := c.Peek(2); ok {
+		t.Fatal("expected 2 to be evicted")
+	}
+
+	if _, ok := c.Peek(1); !ok {
+		t.Fatal("expected 1 to be kept")
+	}
+
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("evicted = %v, want [2]", evicted)
+	}
+
+	if stats := c.Stats(); stats.Entries != 2 || stats.Evictions != 1 {
+		t.Fatalf("stats = %+v, want 2 entries and 1 eviction", stats)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	c := newCache[int, string](Policy{TTL: 20 * time.Millisecond}, nil)
+
+	c.Set(1, "one", false)
+
+	if _, ok := c.Get(1); !ok {
+		t.Fatal("expected 1 before expiry")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := c.Get(1); ok {
+		t.Fatal("expected 1 to expire")
+	}
+
+	// An expired entry doesn't prevent setting without update
+	if !c.Set(1, "uno", false) {
+		t.Fatal("expected set over an expired entry to store")
+	}
+
+	if v, _ := c.Peek(1); v != "uno" {
+		t.Fatalf("value = %q, want uno", v)
+	}
+
+	if stats := c.Stats(); stats.Evictions != 1 {
+		t.Fatalf("evictions = %d, want 1", stats.Evictions)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	c := newCache[int, string](Policy{}, nil)
+
+	c.Set(1, "one", false)
+	c.Get(1)
+	c.Get(1)
+	c.Get(2)
+	c.Peek(2)
+
+	stats := c.Stats()
+
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("stats = %+v, want 2 hits, 1 miss, 1 entry", stats)
+	}
+
+	if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Fatalf("hit ratio = %v, want 2/3", ratio)
+	}
+}
+
+func TestCacheOnEvictCanUseCache(t *testing.T) {
+	var c *cache[int, string]
+
+	done := make(chan struct{})
+
+	c = newCache(Policy{MaxEntries: 1}, func(k int, _ string) {
+		// Would deadlock if called with the lock held
+		c.Peek(k)
+		c.Len()
+	})
+
+	go func() {
+		defer close(done)
+
+		c.Set(1, "one", false)
+		c.Set(2, "two", false)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("onEvict deadlocked")
+	}
+}
+
+func TestMembersIncompleteAfterEviction(t *testing.T) {
+	s := NewMember(WithMemberPolicy(Policy{MaxEntries: 2}))
+
+	for i := 1; i <= 2; i++ {
+		_ = s.MemberSet(&valour.Member{ID: valour.MemberID(i), UserID: valour.UserID(i), PlanetID: 1}, false)
+	}
+
+	if members, err := s.Members(1); err != nil || len(members) != 2 {
+		t.Fatalf("Members = %v, %v, want 2 members", members, err)
+	}
+
+	_ = s.MemberSet(&valour.Member{ID: 3, UserID: 3, PlanetID: 2}, false)
+
+	if _, err := s.Members(1); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Members err = %v, want ErrNotFound for a partially evicted planet", err)
+	}
+
+	if members, err := s.Members(2); err != nil || len(members) != 1 {
+		t.Fatalf("Members = %v, %v, want 1 member", members, err)
+	}
+
+	if _, err := s.MemberByUser(1, 1); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("MemberByUser err = %v, want ErrNotFound for the evicted member", err)
+	}
+}
+
+func TestMemberByUserRecordsOnce(t *testing.T) {
+	s := NewMember()
+
+	_ = s.MemberSet(&valour.Member{ID: 1, UserID: 1, PlanetID: 1}, false)
+
+	_, _ = s.MemberByUser(1, 1)
+	_, _ = s.MemberByUser(1, 2)
+	_, _ = s.MemberByUser(2, 1)
+
+	if stats := s.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Fatalf("stats = %+v, want 1 hit and 2 misses", stats)
+	}
+}
+
+func TestMessageEvictionUnindexes(t *testing.T) {
+	s := NewMessage(WithMessagePolicy(Policy{MaxEntries: 2}))
+
+	for i := 1; i <= 3; i++ {
+		_ = s.MessageSet(&valour.Message{ID: valour.MessageID(i), ChannelID: valour.ChannelID(i)}, false)
+	}
+
+	s.indexMut.Lock()
+	_, stale := s.channels[1]
+	indexed := len(s.channels)
+	s.indexMut.Unlock()
+
+	if stale || indexed != 2 {
+		t.Fatalf("index has %d channels (stale: %v), want 2 without the evicted message", indexed, stale)
+	}
+
+	if _, err := s.Messages(1); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Messages err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIndexingWithExpiredEntriesDoesNotDeadlock(t *testing.T) {
+	members := NewMember(WithMemberPolicy(Policy{TTL: 200 * time.Nanosecond}))
+	messages := NewMessage(WithMessagePolicy(Policy{TTL: 200 * time.Nanosecond}))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// Entries expire between being set and indexed, evicting them while the index is locked
+		for i := range 2000 {
+			id := i % 10
+
+			_ = members.MemberSet(&valour.Member{ID: valour.MemberID(id), UserID: valour.UserID(id), PlanetID: 1}, true)
+			_ = members.MemberRemove(valour.MemberID((i + 5) % 10))
+			_ = messages.MessageSet(&valour.Message{ID: valour.MessageID(id), ChannelID: 1}, true)
+			_ = messages.MessageRemove(valour.MessageID((i + 5) % 10))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("storing expired entries deadlocked")
+	}
+}
+
+func TestMessagesBoundedByDefault(t *testing.T) {
+	s := NewMessage()
+
+	for i := range defaultMessagePolicy.MaxEntries + 10 {
+		_ = s.MessageSet(&valour.Message{ID: valour.MessageID(i + 1), ChannelID: 1}, false)
+	}
+
+	if stats := s.Stats(); stats.Entries != defaultMessagePolicy.MaxEntries || stats.Evictions != 10 {
+		t.Fatalf("stats = %+v, want %d entries after 10 evictions", stats, defaultMessagePolicy.MaxEntries)
+	}
+
+	// A zero policy keeps every message
+	s = NewMessage(WithMessagePolicy(Policy{}))
+
+	for i := range defaultMessagePolicy.MaxEntries + 10 {
+		_ = s.MessageSet(&valour.Message{ID: valour.MessageID(i + 1), ChannelID: 1}, false)
+	}
+
+	if stats := s.Stats(); stats.Evictions != 0 {
+		t.Fatalf("evictions = %d, want none without a limit", stats.Evictions)
+	}
+}
+
+func TestMembersCompleteAfterRefetch(t *testing.T) {
+	s := NewMember(WithMemberPolicy(Policy{MaxEntries: 2}))
+
+	member := func(id int, planetID valour.PlanetID) *valour.Member {
+		return &valour.Member{ID: valour.MemberID(id), UserID: valour.UserID(id), PlanetID: planetID}
+	}
+
+	_ = s.MemberSet(member(1, 1), true)
+	_ = s.MemberSet(member(2, 1), true)
+	_ = s.MemberSet(member(3, 2), true)
+
+	if _, err := s.Members(1); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Members err = %v, want ErrNotFound after an eviction", err)
+	}
+
+	// A fetch whose members were evicted again before it finished doesn't complete the list
+	_ = s.MemberSet(member(1, 1), true)
+	_ = s.MemberSet(member(3, 2), true)
+	_ = s.MemberSet(member(2, 1), true)
+
+	if err := s.MembersComplete(1, []valour.MemberID{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Members(1); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("Members err = %v, want ErrNotFound while a fetched member is missing", err)
+	}
+
+	// Refetching the full list stores every member again
+	_ = s.MemberSet(member(1, 1), true)
+	_ = s.MemberSet(member(2, 1), true)
+
+	if err := s.MembersComplete(1, []valour.MemberID{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if members, err := s.Members(1); err != nil || len(members) != 2 {
+		t.Fatalf("Members = %v, %v, want both members after refetching", members, err)
+	}
+}
@@ -2,6 +2,7 @@ package defaultstore
 
 import (
 	"slices"
+	"sync"
 
 	valour "github.com/auroradevllc/valourgo"
 	"github.com/auroradevllc/valourgo/state/store"
@@ -9,16 +10,24 @@ import (
 )
 
 type Channel struct {
+	counter
+
+	// indexMut serializes writes to planetChannels, reads go through the map directly
+	indexMut       sync.Mutex
 	channels       cmap.ConcurrentMap[valour.ChannelID, valour.Channel]
 	planetChannels cmap.ConcurrentMap[valour.PlanetID, []valour.ChannelID]
+	evictOnLeave   bool
 }
 
 var _ store.ChannelStore = (*Channel)(nil)
 
-func NewChannel() *Channel {
+func NewChannel(opts ...Option) *Channel {
+	cfg := newConfig(opts)
+
 	return &Channel{
 		channels:       cmap.NewStringer[valour.ChannelID, valour.Channel](),
 		planetChannels: cmap.NewStringer[valour.PlanetID, []valour.ChannelID](),
+		evictOnLeave:   cfg.evictOnLeave,
 	}
 }
 
@@ -28,10 +37,14 @@ func (s *Channel) Reset() error {
 	return nil
 }
 
+func (s *Channel) Stats() store.Stats {
+	return s.stats(s.channels.Count())
+}
+
 func (s *Channel) Channel(id valour.ChannelID) (*valour.Channel, error) {
 	item, ok := s.channels.Get(id)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -41,7 +54,7 @@ func (s *Channel) Channel(id valour.ChannelID) (*valour.Channel, error) {
 func (s *Channel) Channels(planet valour.PlanetID) ([]valour.Channel, error) {
 	ids, ok := s.planetChannels.Get(planet)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -50,8 +63,9 @@ func (s *Channel) Channels(planet valour.PlanetID) ([]valour.Channel, error) {
 	for _, id := range ids {
 		ch, ok := s.channels.Get(id)
 
+		// Removed between reading the index and the channel
 		if !ok {
-			return nil, store.ErrNotFound
+			continue
 		}
 
 		channels = append(channels, ch)
@@ -61,16 +75,20 @@ func (s *Channel) Channels(planet valour.PlanetID) ([]valour.Channel, error) {
 }
 
 func (s *Channel) ChannelSet(c *valour.Channel, update bool) error {
-	cpy := *c
+	if update {
+		s.channels.Set(c.ID, *c)
+	} else if !s.channels.SetIfAbsent(c.ID, *c) {
+		return nil
+	}
 
-	s.channels.Set(c.ID, cpy)
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
 
 	list, _ := s.planetChannels.Get(c.PlanetID)
 
+	// Index slices are shared with readers, so they're always copied rather than modified in place
 	if !slices.Contains(list, c.ID) {
-		list = append(list, c.ID)
-
-		s.planetChannels.Set(c.PlanetID, list)
+		s.planetChannels.Set(c.PlanetID, append(slices.Clip(list), c.ID))
 	}
 
 	return nil
@@ -79,16 +97,31 @@ func (s *Channel) ChannelSet(c *valour.Channel, update bool) error {
 func (s *Channel) ChannelRemove(c *valour.Channel) error {
 	s.channels.Remove(c.ID)
 
-	list, _ := s.planetChannels.Get(c.PlanetID)
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
 
-	if !slices.Contains(list, c.ID) {
-		list = append(list, c.ID)
+	list, ok := s.planetChannels.Get(c.PlanetID)
 
-		idx := slices.Index(list, c.ID)
+	if idx := slices.Index(list, c.ID); ok && idx != -1 {
+		s.planetChannels.Set(c.PlanetID, slices.Delete(slices.Clone(list), idx, idx+1))
+	}
 
-		if idx != -1 {
-			s.planetChannels.Set(c.PlanetID, slices.Delete(list, idx, idx))
-		}
+	return nil
+}
+
+// PlanetLeave drops all channels of a planet, if the store evicts on leave
+func (s *Channel) PlanetLeave(id valour.PlanetID) error {
+	if !s.evictOnLeave {
+		return nil
+	}
+
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
+
+	ids, _ := s.planetChannels.Pop(id)
+
+	for _, channelID := range ids {
+		s.channels.Remove(channelID)
 	}
 
 	return nil
@@ -2,13 +2,68 @@ package defaultstore
 
 import "github.com/auroradevllc/valourgo/state/store"
 
-func New() *store.Cabinet {
+// defaultMessagePolicy keeps the most recently used messages, as every message received is stored
+var defaultMessagePolicy = Policy{MaxEntries: 5000}
+
+type config struct {
+	members      Policy
+	users        Policy
+	messages     Policy
+	evictOnLeave bool
+}
+
+type Option func(c *config)
+
+// WithMemberPolicy limits how many members are kept, and for how long
+func WithMemberPolicy(p Policy) Option {
+	return func(c *config) {
+		c.members = p
+	}
+}
+
+// WithUserPolicy limits how many users are kept, and for how long
+func WithUserPolicy(p Policy) Option {
+	return func(c *config) {
+		c.users = p
+	}
+}
+
+// WithMessagePolicy limits how many messages are kept, and for how long.
+// By default the 5000 most recently used messages are kept, and a zero Policy keeps every message.
+func WithMessagePolicy(p Policy) Option {
+	return func(c *config) {
+		c.messages = p
+	}
+}
+
+// WithEvictOnLeave drops all of a planet's data when the client leaves it
+func WithEvictOnLeave() Option {
+	return func(c *config) {
+		c.evictOnLeave = true
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{messages: defaultMessagePolicy}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+func New(opts ...Option) *store.Cabinet {
 	return &store.Cabinet{
 		MeStore:      NewMe(),
-		ChannelStore: NewChannel(),
-		PlanetStore:  NewPlanet(),
-		MemberStore:  NewMember(),
-		RoleStore:    NewRole(),
-		EmojiStore:   NewEmoji(),
+		ChannelStore: NewChannel(opts...),
+		PlanetStore:  NewPlanet(opts...),
+		MemberStore:  NewMember(opts...),
+		RoleStore:    NewRole(opts...),
+		EmojiStore:   NewEmoji(opts...),
+		UserStore:    NewUser(opts...),
+		MessageStore: NewMessage(opts...),
+
+		PermissionsNodeStore: NewPermissionsNode(opts...),
 	}
 }
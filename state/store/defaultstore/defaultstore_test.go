@@ -0,0 +1,15 @@
+package defaultstore_test
+
+import (
+	"testing"
+
+	"github.com/auroradevllc/valourgo/state/store"
+	"github.com/auroradevllc/valourgo/state/store/defaultstore"
+	"github.com/auroradevllc/valourgo/state/store/storetest"
+)
+
+func TestDefaultStore(t *testing.T) {
+	storetest.Run(t, func() *store.Cabinet {
+		return defaultstore.New()
+	})
+}
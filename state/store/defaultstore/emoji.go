@@ -8,16 +8,21 @@ import (
 
 type emojis = cmap.ConcurrentMap[valour.EmojiID, valour.Emoji]
 
-func NewEmoji() *Emoji {
+func NewEmoji(opts ...Option) *Emoji {
+	cfg := newConfig(opts)
+
 	return &Emoji{
-		planets: cmap.NewStringer[valour.PlanetID, emojis](),
+		planets:      cmap.NewStringer[valour.PlanetID, emojis](),
+		evictOnLeave: cfg.evictOnLeave,
 	}
 }
 
 var _ store.EmojiStore = (*Emoji)(nil)
 
 type Emoji struct {
-	planets cmap.ConcurrentMap[valour.PlanetID, emojis]
+	counter
+	planets      cmap.ConcurrentMap[valour.PlanetID, emojis]
+	evictOnLeave bool
 }
 
 func (s *Emoji) Reset() error {
@@ -25,16 +30,27 @@ func (s *Emoji) Reset() error {
 	return nil
 }
 
+func (s *Emoji) Stats() store.Stats {
+	var entries int
+
+	s.planets.IterCb(func(_ valour.PlanetID, planet emojis) {
+		entries += planet.Count()
+	})
+
+	return s.stats(entries)
+}
+
 func (s *Emoji) Emoji(planetID valour.PlanetID, emojiID valour.EmojiID) (*valour.Emoji, error) {
 	planet, ok := s.planets.Get(planetID)
 
 	if !ok {
+		s.record(false)
 		return nil, store.ErrNotFound
 	}
 
 	emoji, ok := planet.Get(emojiID)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -44,7 +60,7 @@ func (s *Emoji) Emoji(planetID valour.PlanetID, emojiID valour.EmojiID) (*valour
 func (s *Emoji) Emojis(planetID valour.PlanetID) ([]valour.Emoji, error) {
 	planet, ok := s.planets.Get(planetID)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -58,17 +74,53 @@ func (s *Emoji) Emojis(planetID valour.PlanetID) ([]valour.Emoji, error) {
 }
 
 func (s *Emoji) EmojiSet(planetID valour.PlanetID, emojis []valour.Emoji, update bool) error {
-	planet, ok := s.planets.Get(planetID)
-
-	if ok && !update {
+	if s.planets.Has(planetID) && !update {
 		return nil
 	}
 
-	planet.Clear()
+	// Build the new set first and swap it in, so readers never see a partially filled planet
+	planet := cmap.NewStringer[valour.EmojiID, valour.Emoji]()
 
 	for _, emoji := range emojis {
 		planet.Set(emoji.ID, emoji)
 	}
 
+	s.planets.Set(planetID, planet)
+
+	return nil
+}
+
+func (s *Emoji) EmojiAdd(emoji *valour.Emoji, update bool) error {
+	s.planets.Upsert(emoji.PlanetID, emojis{}, func(exist bool, planet emojis, _ emojis) emojis {
+		if !exist {
+			planet = cmap.NewStringer[valour.EmojiID, valour.Emoji]()
+		}
+
+		if update {
+			planet.Set(emoji.ID, *emoji)
+		} else {
+			planet.SetIfAbsent(emoji.ID, *emoji)
+		}
+
+		return planet
+	})
+
+	return nil
+}
+
+func (s *Emoji) EmojiRemove(planetID valour.PlanetID, emojiID valour.EmojiID) error {
+	if planet, ok := s.planets.Get(planetID); ok {
+		planet.Remove(emojiID)
+	}
+
+	return nil
+}
+
+// PlanetLeave drops all emojis of a planet, if the store evicts on leave
+func (s *Emoji) PlanetLeave(id valour.PlanetID) error {
+	if s.evictOnLeave {
+		s.planets.Remove(id)
+	}
+
 	return nil
 }
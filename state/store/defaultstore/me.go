@@ -14,6 +14,7 @@ func NewMe() *Me {
 var _ store.MeStore = (*Me)(nil)
 
 type Me struct {
+	counter
 	mut sync.RWMutex
 	me  valour.User
 }
@@ -25,19 +26,37 @@ func (s *Me) Reset() error {
 	return nil
 }
 
+func (s *Me) Stats() store.Stats {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	if s.me.ID.IsValid() {
+		return s.stats(1)
+	}
+
+	return s.stats(0)
+}
+
 func (s *Me) Me() (*valour.User, error) {
 	s.mut.RLock()
 	me := s.me
 	s.mut.RUnlock()
 
-	if !me.ID.IsValid() {
+	if !s.record(me.ID.IsValid()) {
 		return nil, store.ErrNotFound
 	}
 
-	return &s.me, nil
+	return &me, nil
 }
 
 func (s *Me) MyselfSet(u valour.User, update bool) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.me.ID.IsValid() && !update {
+		return nil
+	}
+
 	s.me = u
 	return nil
 }
@@ -1,6 +1,9 @@
 package defaultstore
 
 import (
+	"sync"
+	"sync/atomic"
+
 	valour "github.com/auroradevllc/valourgo"
 	"github.com/auroradevllc/valourgo/state/store"
 	cmap "github.com/orcaman/concurrent-map/v2"
@@ -8,20 +11,35 @@ import (
 
 type planetMembers struct {
 	memberIDs cmap.ConcurrentMap[valour.UserID, valour.MemberID]
+
+	// incomplete is set once the policy has evicted any of the planet's members,
+	// until all of them are stored again. It's changed with indexMut held.
+	incomplete atomic.Bool
 }
 
 var _ store.MemberStore = (*Member)(nil)
 
-func NewMember() *Member {
-	return &Member{
-		members: cmap.NewStringer[valour.MemberID, valour.Member](),
-		planets: cmap.NewStringer[valour.PlanetID, *planetMembers](),
+func NewMember(opts ...Option) *Member {
+	cfg := newConfig(opts)
+
+	s := &Member{
+		planets:      cmap.NewStringer[valour.PlanetID, *planetMembers](),
+		evictOnLeave: cfg.evictOnLeave,
 	}
+
+	s.members = newCache(cfg.members, s.evicted)
+
+	return s
 }
 
 type Member struct {
-	members cmap.ConcurrentMap[valour.MemberID, valour.Member]
-	planets cmap.ConcurrentMap[valour.PlanetID, *planetMembers]
+	members *cache[valour.MemberID, valour.Member]
+
+	// planets indexes member IDs by planet and user, with changes guarded by indexMut
+	indexMut sync.Mutex
+	planets  cmap.ConcurrentMap[valour.PlanetID, *planetMembers]
+
+	evictOnLeave bool
 }
 
 func (s *Member) Reset() error {
@@ -30,6 +48,10 @@ func (s *Member) Reset() error {
 	return nil
 }
 
+func (s *Member) Stats() store.Stats {
+	return s.members.Stats()
+}
+
 func (s *Member) Member(id valour.MemberID) (*valour.Member, error) {
 	m, ok := s.members.Get(id)
 
@@ -41,72 +63,159 @@ func (s *Member) Member(id valour.MemberID) (*valour.Member, error) {
 }
 
 func (s *Member) MemberByUser(id valour.PlanetID, userID valour.UserID) (*valour.Member, error) {
+	m, ok := s.memberByUser(id, userID)
+
+	if !s.members.record(ok) {
+		return nil, store.ErrNotFound
+	}
+
+	return &m, nil
+}
+
+func (s *Member) memberByUser(id valour.PlanetID, userID valour.UserID) (valour.Member, bool) {
 	planet, ok := s.planets.Get(id)
 
 	if !ok {
-		return nil, store.ErrNotFound
+		return valour.Member{}, false
 	}
 
 	memberID, ok := planet.memberIDs.Get(userID)
 
 	if !ok {
-		return nil, store.ErrNotFound
+		return valour.Member{}, false
 	}
 
-	return s.Member(memberID)
+	return s.members.get(memberID, true)
 }
 
+// Members returns the stored members of a planet.
+// Once the policy has evicted any of them, the list is incomplete and this reports a miss.
 func (s *Member) Members(id valour.PlanetID) ([]valour.Member, error) {
 	planet, ok := s.planets.Get(id)
 
-	if !ok {
+	if !s.members.record(ok && !planet.incomplete.Load()) {
 		return nil, store.ErrNotFound
 	}
 
 	var members = make([]valour.Member, 0, planet.memberIDs.Count())
 
 	for t := range planet.memberIDs.IterBuffered() {
-		member, err := s.Member(t.Val)
+		member, ok := s.members.Peek(t.Val)
 
-		if err != nil {
-			return nil, err
+		// Removed between reading the index and the member
+		if !ok {
+			continue
 		}
 
-		members = append(members, *member)
+		members = append(members, member)
 	}
 
 	return members, nil
 }
 
 func (s *Member) MemberSet(m *valour.Member, update bool) error {
-	if !s.members.Has(m.ID) || update {
-		s.members.Set(m.ID, *m)
+	if !s.members.Set(m.ID, *m, update) {
+		return nil
+	}
 
-		planet, ok := s.planets.Get(m.PlanetID)
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
 
-		if !ok {
-			s.planets.Set(m.PlanetID, &planetMembers{
-				memberIDs: cmap.NewStringer[valour.UserID, valour.MemberID](),
-			})
-		} else {
-			planet.memberIDs.Set(m.UserID, m.ID)
-		}
+	// Evicted or removed before it could be indexed
+	if !s.members.Contains(m.ID) {
+		return nil
 	}
 
+	planet := s.planets.Upsert(m.PlanetID, nil, func(exists bool, planet, _ *planetMembers) *planetMembers {
+		if exists {
+			return planet
+		}
+
+		return &planetMembers{
+			memberIDs: cmap.NewStringer[valour.UserID, valour.MemberID](),
+		}
+	})
+
+	planet.memberIDs.Set(m.UserID, m.ID)
+
 	return nil
 }
 
 func (s *Member) MemberRemove(memberID valour.MemberID) error {
 	// Utilize members cache to remove from planet
-	s.members.RemoveCb(memberID, func(_ valour.MemberID, member valour.Member, exists bool) bool {
-		planets, ok := s.planets.Get(member.PlanetID)
+	if member, ok := s.members.Remove(memberID); ok {
+		s.unindex(memberID, member)
+	}
 
-		if ok {
-			planets.memberIDs.Remove(member.UserID)
-		}
+	return nil
+}
+
+// PlanetLeave drops all members of a planet, if the store evicts on leave
+func (s *Member) PlanetLeave(id valour.PlanetID) error {
+	if !s.evictOnLeave {
+		return nil
+	}
 
-		return true
+	s.indexMut.Lock()
+	s.planets.Remove(id)
+	s.indexMut.Unlock()
+
+	s.members.RemoveFunc(func(_ valour.MemberID, m valour.Member) bool {
+		return m.PlanetID == id
 	})
 
 	return nil
 }
+
+// MembersComplete marks a planet's members as complete again, if all of the fetched members are still stored
+func (s *Member) MembersComplete(id valour.PlanetID, memberIDs []valour.MemberID) error {
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
+
+	planet, ok := s.planets.Get(id)
+
+	if !ok {
+		return nil
+	}
+
+	for _, memberID := range memberIDs {
+		if !s.members.Contains(memberID) {
+			return nil
+		}
+	}
+
+	planet.incomplete.Store(false)
+
+	return nil
+}
+
+// evicted marks the planet of a member evicted by the policy as incomplete
+func (s *Member) evicted(memberID valour.MemberID, member valour.Member) {
+	s.indexMut.Lock()
+
+	if planet, ok := s.planets.Get(member.PlanetID); ok {
+		planet.incomplete.Store(true)
+	}
+
+	s.indexMut.Unlock()
+
+	s.unindex(memberID, member)
+}
+
+// unindex removes a member from its planet's index, unless it was stored again since being removed
+func (s *Member) unindex(memberID valour.MemberID, member valour.Member) {
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
+
+	if s.members.Contains(memberID) {
+		return
+	}
+
+	planet, ok := s.planets.Get(member.PlanetID)
+
+	if ok {
+		planet.memberIDs.RemoveCb(member.UserID, func(_ valour.UserID, id valour.MemberID, exists bool) bool {
+			return exists && id == memberID
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package defaultstore
+
+import (
+	"slices"
+	"sync"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+var _ store.MessageStore = (*Message)(nil)
+
+func NewMessage(opts ...Option) *Message {
+	cfg := newConfig(opts)
+
+	s := &Message{
+		channels:     make(map[valour.ChannelID]map[valour.MessageID]struct{}),
+		evictOnLeave: cfg.evictOnLeave,
+	}
+
+	s.messages = newCache(cfg.messages, s.unindex)
+
+	return s
+}
+
+type Message struct {
+	messages *cache[valour.MessageID, valour.Message]
+
+	// channels indexes message IDs by channel, guarded by indexMut
+	indexMut sync.Mutex
+	channels map[valour.ChannelID]map[valour.MessageID]struct{}
+
+	evictOnLeave bool
+}
+
+func (s *Message) Reset() error {
+	s.messages.Clear()
+
+	s.indexMut.Lock()
+	s.channels = make(map[valour.ChannelID]map[valour.MessageID]struct{})
+	s.indexMut.Unlock()
+
+	return nil
+}
+
+func (s *Message) Stats() store.Stats {
+	return s.messages.Stats()
+}
+
+func (s *Message) Message(id valour.MessageID) (*valour.Message, error) {
+	m, ok := s.messages.Get(id)
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	return &m, nil
+}
+
+func (s *Message) Messages(channelID valour.ChannelID) ([]valour.Message, error) {
+	s.indexMut.Lock()
+	index, ok := s.channels[channelID]
+	ids := make([]valour.MessageID, 0, len(index))
+
+	for id := range index {
+		ids = append(ids, id)
+	}
+	s.indexMut.Unlock()
+
+	if !s.messages.record(ok) {
+		return nil, store.ErrNotFound
+	}
+
+	// Message IDs are snowflakes, so sorting them sorts by time sent
+	slices.Sort(ids)
+
+	messages := make([]valour.Message, 0, len(ids))
+
+	for _, id := range ids {
+		m, ok := s.messages.Peek(id)
+
+		// Removed between reading the index and the message
+		if !ok {
+			continue
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, nil
+}
+
+func (s *Message) MessageSet(m *valour.Message, update bool) error {
+	if !s.messages.Set(m.ID, *m, update) {
+		return nil
+	}
+
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
+
+	// Evicted or removed before it could be indexed
+	if !s.messages.Contains(m.ID) {
+		return nil
+	}
+
+	index, ok := s.channels[m.ChannelID]
+
+	if !ok {
+		index = make(map[valour.MessageID]struct{})
+		s.channels[m.ChannelID] = index
+	}
+
+	index[m.ID] = struct{}{}
+
+	return nil
+}
+
+func (s *Message) MessageRemove(id valour.MessageID) error {
+	if m, ok := s.messages.Remove(id); ok {
+		s.unindex(id, m)
+	}
+
+	return nil
+}
+
+// PlanetLeave drops all messages sent in a planet, if the store evicts on leave
+func (s *Message) PlanetLeave(id valour.PlanetID) error {
+	if !s.evictOnLeave {
+		return nil
+	}
+
+	var channels []valour.ChannelID
+
+	s.messages.RemoveFunc(func(_ valour.MessageID, m valour.Message) bool {
+		if m.PlanetID != id {
+			return false
+		}
+
+		channels = append(channels, m.ChannelID)
+
+		return true
+	})
+
+	s.indexMut.Lock()
+	for _, channelID := range channels {
+		delete(s.channels, channelID)
+	}
+	s.indexMut.Unlock()
+
+	return nil
+}
+
+// unindex removes a message from its channel's index, unless it was stored again since being removed
+func (s *Message) unindex(id valour.MessageID, m valour.Message) {
+	s.indexMut.Lock()
+	defer s.indexMut.Unlock()
+
+	if s.messages.Contains(id) {
+		return
+	}
+
+	index, ok := s.channels[m.ChannelID]
+
+	if !ok {
+		return
+	}
+
+	delete(index, id)
+
+	if len(index) == 0 {
+		delete(s.channels, m.ChannelID)
+	}
+}
@@ -0,0 +1,112 @@
+package defaultstore
+
+import (
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+	cmap "github.com/orcaman/concurrent-map/v2"
+)
+
+func NewPermissionsNode(opts ...Option) *PermissionsNode {
+	cfg := newConfig(opts)
+
+	return &PermissionsNode{
+		planets:      cmap.NewStringer[valour.PlanetID, nodeMap](),
+		evictOnLeave: cfg.evictOnLeave,
+	}
+}
+
+var _ store.PermissionsNodeStore = (*PermissionsNode)(nil)
+
+type nodeMap = cmap.ConcurrentMap[valour.PermissionsNodeID, valour.PermissionsNode]
+
+type PermissionsNode struct {
+	counter
+	planets      cmap.ConcurrentMap[valour.PlanetID, nodeMap]
+	evictOnLeave bool
+}
+
+func (s *PermissionsNode) Reset() error {
+	s.planets.Clear()
+	return nil
+}
+
+func (s *PermissionsNode) Stats() store.Stats {
+	var entries int
+
+	s.planets.IterCb(func(_ valour.PlanetID, planet nodeMap) {
+		entries += planet.Count()
+	})
+
+	return s.stats(entries)
+}
+
+func (s *PermissionsNode) PermissionsNode(planetID valour.PlanetID, id valour.PermissionsNodeID) (*valour.PermissionsNode, error) {
+	planet, ok := s.planets.Get(planetID)
+
+	if !ok {
+		s.record(false)
+		return nil, store.ErrNotFound
+	}
+
+	node, ok := planet.Get(id)
+
+	if !s.record(ok) {
+		return nil, store.ErrNotFound
+	}
+
+	return &node, nil
+}
+
+func (s *PermissionsNode) PermissionsNodes(planetID valour.PlanetID) ([]valour.PermissionsNode, error) {
+	planet, ok := s.planets.Get(planetID)
+
+	if !s.record(ok) {
+		return nil, store.ErrNotFound
+	}
+
+	nodes := make([]valour.PermissionsNode, 0, planet.Count())
+
+	for v := range planet.IterBuffered() {
+		nodes = append(nodes, v.Val)
+	}
+
+	return nodes, nil
+}
+
+func (s *PermissionsNode) PermissionsNodeSet(c *valour.PermissionsNode, update bool) error {
+	planet := s.planets.Upsert(c.PlanetID, nodeMap{}, func(exists bool, planet, _ nodeMap) nodeMap {
+		if exists {
+			return planet
+		}
+
+		return cmap.NewStringer[valour.PermissionsNodeID, valour.PermissionsNode]()
+	})
+
+	if update {
+		planet.Set(c.ID, *c)
+	} else {
+		planet.SetIfAbsent(c.ID, *c)
+	}
+
+	return nil
+}
+
+func (s *PermissionsNode) PermissionsNodeRemove(planetID valour.PlanetID, id valour.PermissionsNodeID) error {
+	planet, ok := s.planets.Get(planetID)
+
+	if !ok {
+		return nil
+	}
+
+	planet.Remove(id)
+	return nil
+}
+
+// PlanetLeave drops all permissions nodes of a planet, if the store evicts on leave
+func (s *PermissionsNode) PlanetLeave(id valour.PlanetID) error {
+	if s.evictOnLeave {
+		s.planets.Remove(id)
+	}
+
+	return nil
+}
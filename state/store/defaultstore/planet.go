@@ -7,12 +7,17 @@ import (
 )
 
 type Planet struct {
-	planets cmap.ConcurrentMap[valour.PlanetID, valour.Planet]
+	counter
+	planets      cmap.ConcurrentMap[valour.PlanetID, valour.Planet]
+	evictOnLeave bool
 }
 
-func NewPlanet() *Planet {
+func NewPlanet(opts ...Option) *Planet {
+	cfg := newConfig(opts)
+
 	return &Planet{
-		planets: cmap.NewStringer[valour.PlanetID, valour.Planet](),
+		planets:      cmap.NewStringer[valour.PlanetID, valour.Planet](),
+		evictOnLeave: cfg.evictOnLeave,
 	}
 }
 
@@ -23,10 +28,14 @@ func (s *Planet) Reset() error {
 	return nil
 }
 
+func (s *Planet) Stats() store.Stats {
+	return s.stats(s.planets.Count())
+}
+
 func (s *Planet) Planet(id valour.PlanetID) (*valour.Planet, error) {
 	p, ok := s.planets.Get(id)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -34,7 +43,7 @@ func (s *Planet) Planet(id valour.PlanetID) (*valour.Planet, error) {
 }
 
 func (s *Planet) Planets() ([]valour.Planet, error) {
-	var planets = make([]valour.Planet, 0, len(s.planets.Keys()))
+	var planets = make([]valour.Planet, 0, s.planets.Count())
 
 	s.planets.IterCb(func(_ valour.PlanetID, p valour.Planet) {
 		planets = append(planets, p)
@@ -44,6 +53,12 @@ func (s *Planet) Planets() ([]valour.Planet, error) {
 }
 
 func (s *Planet) PlanetSet(c *valour.Planet, update bool) error {
+	if update {
+		s.planets.Set(c.ID, *c)
+	} else {
+		s.planets.SetIfAbsent(c.ID, *c)
+	}
+
 	return nil
 }
 
@@ -51,3 +66,12 @@ func (s *Planet) PlanetRemove(id valour.PlanetID) error {
 	s.planets.Remove(id)
 	return nil
 }
+
+// PlanetLeave drops a planet, if the store evicts on leave
+func (s *Planet) PlanetLeave(id valour.PlanetID) error {
+	if s.evictOnLeave {
+		s.planets.Remove(id)
+	}
+
+	return nil
+}
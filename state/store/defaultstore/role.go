@@ -6,9 +6,12 @@ import (
 	cmap "github.com/orcaman/concurrent-map/v2"
 )
 
-func NewRole() *Role {
+func NewRole(opts ...Option) *Role {
+	cfg := newConfig(opts)
+
 	return &Role{
-		planets: cmap.NewStringer[valour.PlanetID, roleMap](),
+		planets:      cmap.NewStringer[valour.PlanetID, roleMap](),
+		evictOnLeave: cfg.evictOnLeave,
 	}
 }
 
@@ -17,7 +20,9 @@ var _ store.RoleStore = (*Role)(nil)
 type roleMap = cmap.ConcurrentMap[valour.RoleID, valour.Role]
 
 type Role struct {
-	planets cmap.ConcurrentMap[valour.PlanetID, roleMap]
+	counter
+	planets      cmap.ConcurrentMap[valour.PlanetID, roleMap]
+	evictOnLeave bool
 }
 
 func (s *Role) Reset() error {
@@ -25,16 +30,27 @@ func (s *Role) Reset() error {
 	return nil
 }
 
+func (s *Role) Stats() store.Stats {
+	var entries int
+
+	s.planets.IterCb(func(_ valour.PlanetID, planet roleMap) {
+		entries += planet.Count()
+	})
+
+	return s.stats(entries)
+}
+
 func (s *Role) Role(planetID valour.PlanetID, roleID valour.RoleID) (*valour.Role, error) {
 	planet, ok := s.planets.Get(planetID)
 
 	if !ok {
+		s.record(false)
 		return nil, store.ErrNotFound
 	}
 
 	role, ok := planet.Get(roleID)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -44,7 +60,7 @@ func (s *Role) Role(planetID valour.PlanetID, roleID valour.RoleID) (*valour.Rol
 func (s *Role) Roles(planetID valour.PlanetID) ([]valour.Role, error) {
 	planet, ok := s.planets.Get(planetID)
 
-	if !ok {
+	if !s.record(ok) {
 		return nil, store.ErrNotFound
 	}
 
@@ -58,16 +74,18 @@ func (s *Role) Roles(planetID valour.PlanetID) ([]valour.Role, error) {
 }
 
 func (s *Role) RoleSet(c *valour.Role, update bool) error {
-	planet, ok := s.planets.Get(c.PlanetID)
+	planet := s.planets.Upsert(c.PlanetID, roleMap{}, func(exists bool, planet, _ roleMap) roleMap {
+		if exists {
+			return planet
+		}
 
-	if !ok {
-		planet = cmap.NewStringer[valour.RoleID, valour.Role]()
+		return cmap.NewStringer[valour.RoleID, valour.Role]()
+	})
 
-		s.planets.Set(c.PlanetID, planet)
-	}
-
-	if !planet.Has(c.ID) || update {
+	if update {
 		planet.Set(c.ID, *c)
+	} else {
+		planet.SetIfAbsent(c.ID, *c)
 	}
 
 	return nil
@@ -83,3 +101,12 @@ func (s *Role) RoleRemove(planetID valour.PlanetID, roleID valour.RoleID) error
 	planet.Remove(roleID)
 	return nil
 }
+
+// PlanetLeave drops all roles of a planet, if the store evicts on leave
+func (s *Role) PlanetLeave(id valour.PlanetID) error {
+	if s.evictOnLeave {
+		s.planets.Remove(id)
+	}
+
+	return nil
+}
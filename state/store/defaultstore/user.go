@@ -0,0 +1,49 @@
+package defaultstore
+
+import (
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+var _ store.UserStore = (*User)(nil)
+
+func NewUser(opts ...Option) *User {
+	cfg := newConfig(opts)
+
+	return &User{
+		users: newCache[valour.UserID, valour.User](cfg.users, nil),
+	}
+}
+
+type User struct {
+	users *cache[valour.UserID, valour.User]
+}
+
+func (s *User) Reset() error {
+	s.users.Clear()
+	return nil
+}
+
+func (s *User) Stats() store.Stats {
+	return s.users.Stats()
+}
+
+func (s *User) User(id valour.UserID) (*valour.User, error) {
+	u, ok := s.users.Get(id)
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	return &u, nil
+}
+
+func (s *User) UserSet(u *valour.User, update bool) error {
+	s.users.Set(u.ID, *u, update)
+	return nil
+}
+
+func (s *User) UserRemove(id valour.UserID) error {
+	s.users.Remove(id)
+	return nil
+}
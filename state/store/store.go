@@ -12,6 +12,41 @@ type Resettable interface {
 	Reset() error
 }
 
+// Stats reports the size and effectiveness of a store
+type Stats struct {
+	Entries   int
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRatio returns the fraction of lookups served from the store, or 0 if there were none
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+// StatsReporter is implemented by stores that track their usage
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// PlanetLeaver is implemented by stores that can drop a planet's data once the client has left it
+type PlanetLeaver interface {
+	PlanetLeave(id valour.PlanetID) error
+}
+
+// MembersCompleter is implemented by member stores that track whether they hold all of a planet's members
+type MembersCompleter interface {
+	// MembersComplete is called with the members of a planet once all of them have been fetched and stored
+	MembersComplete(id valour.PlanetID, memberIDs []valour.MemberID) error
+}
+
 type MeStore interface {
 	Resettable
 
@@ -68,5 +103,40 @@ type EmojiStore interface {
 	Emoji(planetID valour.PlanetID, emojiID valour.EmojiID) (*valour.Emoji, error)
 	Emojis(planetID valour.PlanetID) ([]valour.Emoji, error)
 
+	// EmojiSet replaces the full set of a planet's emojis
 	EmojiSet(planetID valour.PlanetID, emojis []valour.Emoji, update bool) error
+	// EmojiAdd adds a single emoji to its planet's set
+	EmojiAdd(emoji *valour.Emoji, update bool) error
+	EmojiRemove(planetID valour.PlanetID, emojiID valour.EmojiID) error
+}
+
+type PermissionsNodeStore interface {
+	Resettable
+
+	PermissionsNode(planetID valour.PlanetID, id valour.PermissionsNodeID) (*valour.PermissionsNode, error)
+	PermissionsNodes(planetID valour.PlanetID) ([]valour.PermissionsNode, error)
+
+	PermissionsNodeSet(n *valour.PermissionsNode, update bool) error
+	PermissionsNodeRemove(planetID valour.PlanetID, id valour.PermissionsNodeID) error
+}
+
+type UserStore interface {
+	Resettable
+
+	User(id valour.UserID) (*valour.User, error)
+
+	UserSet(u *valour.User, update bool) error
+	UserRemove(id valour.UserID) error
+}
+
+type MessageStore interface {
+	Resettable
+
+	Message(id valour.MessageID) (*valour.Message, error)
+
+	// Messages returns a channel's cached messages, oldest first
+	Messages(channelID valour.ChannelID) ([]valour.Message, error)
+
+	MessageSet(m *valour.Message, update bool) error
+	MessageRemove(id valour.MessageID) error
 }
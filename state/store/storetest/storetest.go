@@ -0,0 +1,1205 @@
+// Package storetest provides a behavioral test suite for store implementations.
+//
+// Custom stores can be checked against the same contract defaultstore follows:
+//
+//	func TestStore(t *testing.T) {
+//		storetest.Run(t, func() *store.Cabinet {
+//			return mystore.New()
+//		})
+//	}
+//
+// The concurrency tests are most useful when run with -race.
+package storetest
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	valour "github.com/auroradevllc/valourgo"
+	"github.com/auroradevllc/valourgo/state/store"
+)
+
+// concurrency is the number of goroutines used by concurrency tests
+const concurrency = 16
+
+const (
+	planetA = valour.PlanetID(1000)
+	planetB = valour.PlanetID(2000)
+)
+
+// Run runs the full suite against a Cabinet.
+// newCabinet is called once per test and must return an empty cabinet.
+func Run(t *testing.T, newCabinet func() *store.Cabinet) {
+	t.Run("Me", func(t *testing.T) {
+		RunMeStore(t, func() store.MeStore { return newCabinet().MeStore })
+	})
+	t.Run("Planet", func(t *testing.T) {
+		RunPlanetStore(t, func() store.PlanetStore { return newCabinet().PlanetStore })
+	})
+	t.Run("Channel", func(t *testing.T) {
+		RunChannelStore(t, func() store.ChannelStore { return newCabinet().ChannelStore })
+	})
+	t.Run("Member", func(t *testing.T) {
+		RunMemberStore(t, func() store.MemberStore { return newCabinet().MemberStore })
+	})
+	t.Run("Role", func(t *testing.T) {
+		RunRoleStore(t, func() store.RoleStore { return newCabinet().RoleStore })
+	})
+	t.Run("Emoji", func(t *testing.T) {
+		RunEmojiStore(t, func() store.EmojiStore { return newCabinet().EmojiStore })
+	})
+	t.Run("PermissionsNode", func(t *testing.T) {
+		RunPermissionsNodeStore(t, func() store.PermissionsNodeStore { return newCabinet().PermissionsNodeStore })
+	})
+	t.Run("User", func(t *testing.T) {
+		RunUserStore(t, func() store.UserStore { return newCabinet().UserStore })
+	})
+	t.Run("Message", func(t *testing.T) {
+		RunMessageStore(t, func() store.MessageStore { return newCabinet().MessageStore })
+	})
+	t.Run("Reset", func(t *testing.T) {
+		testCabinetReset(t, newCabinet())
+	})
+}
+
+// RunMeStore runs the suite against a MeStore
+func RunMeStore(t *testing.T, newStore func() store.MeStore) {
+	t.Run("Empty", func(t *testing.T) {
+		_, err := newStore().Me()
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MyselfSet(valour.User{ID: 1, Name: "first"}, false))
+		mustNil(t, s.MyselfSet(valour.User{ID: 1, Name: "second"}, false))
+
+		me, err := s.Me()
+		mustNil(t, err)
+
+		if me.Name != "first" {
+			t.Errorf("MyselfSet without update replaced existing user: got %q", me.Name)
+		}
+
+		mustNil(t, s.MyselfSet(valour.User{ID: 1, Name: "third"}, true))
+
+		me, err = s.Me()
+		mustNil(t, err)
+
+		if me.Name != "third" {
+			t.Errorf("MyselfSet with update did not replace user: got %q", me.Name)
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MyselfSet(valour.User{ID: 1, Name: "me"}, false))
+
+		me, err := s.Me()
+		mustNil(t, err)
+
+		me.Name = "changed"
+
+		me, err = s.Me()
+		mustNil(t, err)
+
+		if me.Name != "me" {
+			t.Errorf("modifying a returned user changed the store: got %q", me.Name)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MyselfSet(valour.User{ID: 1}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Me()
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			_ = s.MyselfSet(valour.User{ID: valour.UserID(i + 1)}, true)
+			_, _ = s.Me()
+		})
+
+		if _, err := s.Me(); err != nil {
+			t.Errorf("Me after concurrent writes: %v", err)
+		}
+	})
+}
+
+// RunPlanetStore runs the suite against a PlanetStore
+func RunPlanetStore(t *testing.T, newStore func() store.PlanetStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.Planet(planetA)
+		requireNotFound(t, err)
+
+		planets, err := s.Planets()
+		mustNil(t, err)
+
+		if len(planets) != 0 {
+			t.Errorf("expected no planets, got %d", len(planets))
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetA, Name: "first"}, false))
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetA, Name: "second"}, false))
+
+		p, err := s.Planet(planetA)
+		mustNil(t, err)
+
+		if p.Name != "first" {
+			t.Errorf("PlanetSet without update replaced existing planet: got %q", p.Name)
+		}
+
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetA, Name: "third"}, true))
+
+		p, err = s.Planet(planetA)
+		mustNil(t, err)
+
+		if p.Name != "third" {
+			t.Errorf("PlanetSet with update did not replace planet: got %q", p.Name)
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		s := newStore()
+
+		in := &valour.Planet{ID: planetA, Name: "planet"}
+		mustNil(t, s.PlanetSet(in, false))
+
+		in.Name = "changed"
+
+		p, err := s.Planet(planetA)
+		mustNil(t, err)
+
+		p.Name = "changed"
+
+		p, err = s.Planet(planetA)
+		mustNil(t, err)
+
+		if p.Name != "planet" {
+			t.Errorf("store shares memory with callers: got %q", p.Name)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetA}, false))
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetB}, false))
+
+		planets, err := s.Planets()
+		mustNil(t, err)
+
+		requireIDs(t, planets, func(p valour.Planet) valour.PlanetID { return p.ID }, planetA, planetB)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetA}, false))
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetB}, false))
+		mustNil(t, s.PlanetRemove(planetA))
+
+		_, err := s.Planet(planetA)
+		requireNotFound(t, err)
+
+		planets, err := s.Planets()
+		mustNil(t, err)
+
+		requireIDs(t, planets, func(p valour.Planet) valour.PlanetID { return p.ID }, planetB)
+
+		// Removing an unknown planet is not an error
+		mustNil(t, s.PlanetRemove(planetA))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PlanetSet(&valour.Planet{ID: planetA}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Planet(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			id := valour.PlanetID(i + 1)
+
+			_ = s.PlanetSet(&valour.Planet{ID: id}, false)
+			_ = s.PlanetSet(&valour.Planet{ID: id}, true)
+			_, _ = s.Planet(id)
+			_, _ = s.Planets()
+		})
+
+		planets, err := s.Planets()
+		mustNil(t, err)
+
+		if len(planets) != concurrency {
+			t.Errorf("expected %d planets, got %d", concurrency, len(planets))
+		}
+	})
+}
+
+// RunChannelStore runs the suite against a ChannelStore
+func RunChannelStore(t *testing.T, newStore func() store.ChannelStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.Channel(1)
+		requireNotFound(t, err)
+
+		_, err = s.Channels(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 1, PlanetID: planetA, Name: "first"}, false))
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 1, PlanetID: planetA, Name: "second"}, false))
+
+		ch, err := s.Channel(1)
+		mustNil(t, err)
+
+		if ch.Name != "first" {
+			t.Errorf("ChannelSet without update replaced existing channel: got %q", ch.Name)
+		}
+
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 1, PlanetID: planetA, Name: "third"}, true))
+
+		channels, err := s.Channels(planetA)
+		mustNil(t, err)
+
+		if len(channels) != 1 || channels[0].Name != "third" {
+			t.Errorf("ChannelSet with update did not replace channel in planet index: %+v", channels)
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		s := newStore()
+
+		in := &valour.Channel{ID: 1, PlanetID: planetA, Name: "channel"}
+		mustNil(t, s.ChannelSet(in, false))
+
+		in.Name = "changed"
+
+		ch, err := s.Channel(1)
+		mustNil(t, err)
+
+		ch.Name = "changed"
+
+		ch, err = s.Channel(1)
+		mustNil(t, err)
+
+		if ch.Name != "channel" {
+			t.Errorf("store shares memory with callers: got %q", ch.Name)
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 2, PlanetID: planetA}, false))
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 3, PlanetID: planetB}, false))
+
+		channelID := func(c valour.Channel) valour.ChannelID { return c.ID }
+
+		channels, err := s.Channels(planetA)
+		mustNil(t, err)
+		requireIDs(t, channels, channelID, 1, 2)
+
+		channels, err = s.Channels(planetB)
+		mustNil(t, err)
+		requireIDs(t, channels, channelID, 3)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		first := &valour.Channel{ID: 1, PlanetID: planetA}
+		second := &valour.Channel{ID: 2, PlanetID: planetA}
+
+		mustNil(t, s.ChannelSet(first, false))
+		mustNil(t, s.ChannelSet(second, false))
+		mustNil(t, s.ChannelRemove(first))
+
+		_, err := s.Channel(1)
+		requireNotFound(t, err)
+
+		channels, err := s.Channels(planetA)
+		mustNil(t, err)
+		requireIDs(t, channels, func(c valour.Channel) valour.ChannelID { return c.ID }, 2)
+
+		// Removing an unknown channel is not an error, and does not create a planet index
+		mustNil(t, s.ChannelRemove(first))
+		mustNil(t, s.ChannelRemove(&valour.Channel{ID: 5, PlanetID: planetB}))
+
+		_, err = s.Channels(planetB)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.ChannelSet(&valour.Channel{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Channel(1)
+		requireNotFound(t, err)
+
+		_, err = s.Channels(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			ch := &valour.Channel{ID: valour.ChannelID(i + 1), PlanetID: planetA}
+
+			_ = s.ChannelSet(ch, false)
+			_ = s.ChannelSet(ch, true)
+			_, _ = s.Channel(ch.ID)
+			_, _ = s.Channels(planetA)
+		})
+
+		channels, err := s.Channels(planetA)
+		mustNil(t, err)
+
+		if len(channels) != concurrency {
+			t.Errorf("expected %d channels, got %d", concurrency, len(channels))
+		}
+
+		parallel(func(i int) {
+			_ = s.ChannelRemove(&valour.Channel{ID: valour.ChannelID(i + 1), PlanetID: planetA})
+		})
+
+		channels, err = s.Channels(planetA)
+
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(channels) != 0 {
+			t.Errorf("expected all channels to be removed, got %d", len(channels))
+		}
+	})
+}
+
+// RunMemberStore runs the suite against a MemberStore
+func RunMemberStore(t *testing.T, newStore func() store.MemberStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.Member(1)
+		requireNotFound(t, err)
+
+		_, err = s.MemberByUser(planetA, 1)
+		requireNotFound(t, err)
+
+		_, err = s.Members(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MemberSet(&valour.Member{ID: 1, UserID: 10, PlanetID: planetA, Nickname: valour.Ref("first")}, false))
+		mustNil(t, s.MemberSet(&valour.Member{ID: 1, UserID: 10, PlanetID: planetA, Nickname: valour.Ref("second")}, false))
+
+		m, err := s.Member(1)
+		mustNil(t, err)
+
+		if m.Nickname == nil || *m.Nickname != "first" {
+			t.Errorf("MemberSet without update replaced existing member: got %v", m.Nickname)
+		}
+
+		mustNil(t, s.MemberSet(&valour.Member{ID: 1, UserID: 10, PlanetID: planetA, Nickname: valour.Ref("third")}, true))
+
+		m, err = s.MemberByUser(planetA, 10)
+		mustNil(t, err)
+
+		if m.Nickname == nil || *m.Nickname != "third" {
+			t.Errorf("MemberSet with update did not replace member: got %v", m.Nickname)
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		s := newStore()
+
+		// The first member of a planet must be indexed like every other member
+		mustNil(t, s.MemberSet(&valour.Member{ID: 1, UserID: 10, PlanetID: planetA}, false))
+		mustNil(t, s.MemberSet(&valour.Member{ID: 2, UserID: 20, PlanetID: planetA}, false))
+		mustNil(t, s.MemberSet(&valour.Member{ID: 3, UserID: 10, PlanetID: planetB}, false))
+
+		m, err := s.MemberByUser(planetA, 10)
+		mustNil(t, err)
+
+		if m.ID != 1 {
+			t.Errorf("MemberByUser(planetA, 10) returned member %d, expected 1", m.ID)
+		}
+
+		m, err = s.MemberByUser(planetB, 10)
+		mustNil(t, err)
+
+		if m.ID != 3 {
+			t.Errorf("MemberByUser(planetB, 10) returned member %d, expected 3", m.ID)
+		}
+
+		_, err = s.MemberByUser(planetB, 20)
+		requireNotFound(t, err)
+
+		memberID := func(m valour.Member) valour.MemberID { return m.ID }
+
+		members, err := s.Members(planetA)
+		mustNil(t, err)
+		requireIDs(t, members, memberID, 1, 2)
+
+		members, err = s.Members(planetB)
+		mustNil(t, err)
+		requireIDs(t, members, memberID, 3)
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		s := newStore()
+
+		in := &valour.Member{ID: 1, UserID: 10, PlanetID: planetA}
+		mustNil(t, s.MemberSet(in, false))
+
+		in.UserID = 20
+
+		m, err := s.Member(1)
+		mustNil(t, err)
+
+		m.UserID = 20
+
+		m, err = s.Member(1)
+		mustNil(t, err)
+
+		if m.UserID != 10 {
+			t.Errorf("store shares memory with callers: got user %d", m.UserID)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MemberSet(&valour.Member{ID: 1, UserID: 10, PlanetID: planetA}, false))
+		mustNil(t, s.MemberSet(&valour.Member{ID: 2, UserID: 20, PlanetID: planetA}, false))
+		mustNil(t, s.MemberRemove(1))
+
+		_, err := s.Member(1)
+		requireNotFound(t, err)
+
+		_, err = s.MemberByUser(planetA, 10)
+		requireNotFound(t, err)
+
+		members, err := s.Members(planetA)
+		mustNil(t, err)
+		requireIDs(t, members, func(m valour.Member) valour.MemberID { return m.ID }, 2)
+
+		// Removing an unknown member is not an error
+		mustNil(t, s.MemberRemove(1))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MemberSet(&valour.Member{ID: 1, UserID: 10, PlanetID: planetA}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Member(1)
+		requireNotFound(t, err)
+
+		_, err = s.MemberByUser(planetA, 10)
+		requireNotFound(t, err)
+
+		_, err = s.Members(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			m := &valour.Member{ID: valour.MemberID(i + 1), UserID: valour.UserID(i + 100), PlanetID: planetA}
+
+			_ = s.MemberSet(m, false)
+			_ = s.MemberSet(m, true)
+			_, _ = s.Member(m.ID)
+			_, _ = s.MemberByUser(planetA, m.UserID)
+			_, _ = s.Members(planetA)
+		})
+
+		members, err := s.Members(planetA)
+		mustNil(t, err)
+
+		if len(members) != concurrency {
+			t.Errorf("expected %d members, got %d", concurrency, len(members))
+		}
+
+		parallel(func(i int) {
+			_ = s.MemberRemove(valour.MemberID(i + 1))
+		})
+
+		members, err = s.Members(planetA)
+
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(members) != 0 {
+			t.Errorf("expected all members to be removed, got %d", len(members))
+		}
+	})
+}
+
+// RunRoleStore runs the suite against a RoleStore
+func RunRoleStore(t *testing.T, newStore func() store.RoleStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.Role(planetA, 1)
+		requireNotFound(t, err)
+
+		_, err = s.Roles(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.RoleSet(&valour.Role{ID: 1, PlanetID: planetA, Name: "first"}, false))
+		mustNil(t, s.RoleSet(&valour.Role{ID: 1, PlanetID: planetA, Name: "second"}, false))
+
+		r, err := s.Role(planetA, 1)
+		mustNil(t, err)
+
+		if r.Name != "first" {
+			t.Errorf("RoleSet without update replaced existing role: got %q", r.Name)
+		}
+
+		mustNil(t, s.RoleSet(&valour.Role{ID: 1, PlanetID: planetA, Name: "third"}, true))
+
+		r, err = s.Role(planetA, 1)
+		mustNil(t, err)
+
+		if r.Name != "third" {
+			t.Errorf("RoleSet with update did not replace role: got %q", r.Name)
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.RoleSet(&valour.Role{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.RoleSet(&valour.Role{ID: 2, PlanetID: planetA}, false))
+		mustNil(t, s.RoleSet(&valour.Role{ID: 3, PlanetID: planetB}, false))
+
+		_, err := s.Role(planetB, 1)
+		requireNotFound(t, err)
+
+		roleID := func(r valour.Role) valour.RoleID { return r.ID }
+
+		roles, err := s.Roles(planetA)
+		mustNil(t, err)
+		requireIDs(t, roles, roleID, 1, 2)
+
+		roles, err = s.Roles(planetB)
+		mustNil(t, err)
+		requireIDs(t, roles, roleID, 3)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.RoleSet(&valour.Role{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.RoleSet(&valour.Role{ID: 2, PlanetID: planetA}, false))
+		mustNil(t, s.RoleRemove(planetA, 1))
+
+		_, err := s.Role(planetA, 1)
+		requireNotFound(t, err)
+
+		roles, err := s.Roles(planetA)
+		mustNil(t, err)
+		requireIDs(t, roles, func(r valour.Role) valour.RoleID { return r.ID }, 2)
+
+		// Removing an unknown role is not an error
+		mustNil(t, s.RoleRemove(planetA, 1))
+		mustNil(t, s.RoleRemove(planetB, 1))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.RoleSet(&valour.Role{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Role(planetA, 1)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			r := &valour.Role{ID: valour.RoleID(i + 1), PlanetID: planetA}
+
+			_ = s.RoleSet(r, false)
+			_ = s.RoleSet(r, true)
+			_, _ = s.Role(planetA, r.ID)
+			_, _ = s.Roles(planetA)
+		})
+
+		roles, err := s.Roles(planetA)
+		mustNil(t, err)
+
+		if len(roles) != concurrency {
+			t.Errorf("expected %d roles, got %d", concurrency, len(roles))
+		}
+	})
+}
+
+// RunPermissionsNodeStore runs the suite against a PermissionsNodeStore
+func RunPermissionsNodeStore(t *testing.T, newStore func() store.PermissionsNodeStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.PermissionsNode(planetA, 1)
+		requireNotFound(t, err)
+
+		_, err = s.PermissionsNodes(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA, Mask: 1}, false))
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA, Mask: 2}, false))
+
+		r, err := s.PermissionsNode(planetA, 1)
+		mustNil(t, err)
+
+		if r.Mask != 1 {
+			t.Errorf("PermissionsNodeSet without update replaced existing node: got mask %d", r.Mask)
+		}
+
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA, Mask: 3}, true))
+
+		r, err = s.PermissionsNode(planetA, 1)
+		mustNil(t, err)
+
+		if r.Mask != 3 {
+			t.Errorf("PermissionsNodeSet with update did not replace node: got mask %d", r.Mask)
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 2, PlanetID: planetA}, false))
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 3, PlanetID: planetB}, false))
+
+		_, err := s.PermissionsNode(planetB, 1)
+		requireNotFound(t, err)
+
+		nodeID := func(r valour.PermissionsNode) valour.PermissionsNodeID { return r.ID }
+
+		nodes, err := s.PermissionsNodes(planetA)
+		mustNil(t, err)
+		requireIDs(t, nodes, nodeID, 1, 2)
+
+		nodes, err = s.PermissionsNodes(planetB)
+		mustNil(t, err)
+		requireIDs(t, nodes, nodeID, 3)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 2, PlanetID: planetA}, false))
+		mustNil(t, s.PermissionsNodeRemove(planetA, 1))
+
+		_, err := s.PermissionsNode(planetA, 1)
+		requireNotFound(t, err)
+
+		nodes, err := s.PermissionsNodes(planetA)
+		mustNil(t, err)
+		requireIDs(t, nodes, func(r valour.PermissionsNode) valour.PermissionsNodeID { return r.ID }, 2)
+
+		// Removing an unknown node is not an error
+		mustNil(t, s.PermissionsNodeRemove(planetA, 1))
+		mustNil(t, s.PermissionsNodeRemove(planetB, 1))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.PermissionsNode(planetA, 1)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			n := &valour.PermissionsNode{ID: valour.PermissionsNodeID(i + 1), PlanetID: planetA}
+
+			_ = s.PermissionsNodeSet(n, false)
+			_ = s.PermissionsNodeSet(n, true)
+			_, _ = s.PermissionsNode(planetA, n.ID)
+			_, _ = s.PermissionsNodes(planetA)
+		})
+
+		nodes, err := s.PermissionsNodes(planetA)
+		mustNil(t, err)
+
+		if len(nodes) != concurrency {
+			t.Errorf("expected %d nodes, got %d", concurrency, len(nodes))
+		}
+	})
+}
+
+// RunEmojiStore runs the suite against an EmojiStore
+func RunEmojiStore(t *testing.T, newStore func() store.EmojiStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.Emoji(planetA, 1)
+		requireNotFound(t, err)
+
+		_, err = s.Emojis(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.EmojiSet(planetA, []valour.Emoji{{ID: 1, Name: "first"}, {ID: 2}}, false))
+		mustNil(t, s.EmojiSet(planetA, []valour.Emoji{{ID: 1, Name: "second"}}, false))
+
+		e, err := s.Emoji(planetA, 1)
+		mustNil(t, err)
+
+		if e.Name != "first" {
+			t.Errorf("EmojiSet without update replaced existing emojis: got %q", e.Name)
+		}
+
+		// An update replaces the whole set for the planet
+		mustNil(t, s.EmojiSet(planetA, []valour.Emoji{{ID: 1, Name: "third"}}, true))
+
+		e, err = s.Emoji(planetA, 1)
+		mustNil(t, err)
+
+		if e.Name != "third" {
+			t.Errorf("EmojiSet with update did not replace emoji: got %q", e.Name)
+		}
+
+		_, err = s.Emoji(planetA, 2)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.EmojiSet(planetA, []valour.Emoji{{ID: 1}, {ID: 2}}, false))
+		mustNil(t, s.EmojiSet(planetB, []valour.Emoji{{ID: 3}}, false))
+
+		_, err := s.Emoji(planetB, 1)
+		requireNotFound(t, err)
+
+		emojiID := func(e valour.Emoji) valour.EmojiID { return e.ID }
+
+		emojis, err := s.Emojis(planetA)
+		mustNil(t, err)
+		requireIDs(t, emojis, emojiID, 1, 2)
+
+		emojis, err = s.Emojis(planetB)
+		mustNil(t, err)
+		requireIDs(t, emojis, emojiID, 3)
+	})
+
+	t.Run("Single", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.EmojiAdd(&valour.Emoji{ID: 1, PlanetID: planetA, Name: "first"}, false))
+		mustNil(t, s.EmojiAdd(&valour.Emoji{ID: 1, PlanetID: planetA, Name: "second"}, false))
+		mustNil(t, s.EmojiAdd(&valour.Emoji{ID: 2, PlanetID: planetA}, false))
+
+		e, err := s.Emoji(planetA, 1)
+		mustNil(t, err)
+
+		if e.Name != "first" {
+			t.Errorf("EmojiAdd without update replaced existing emoji: got %q", e.Name)
+		}
+
+		mustNil(t, s.EmojiAdd(&valour.Emoji{ID: 1, PlanetID: planetA, Name: "third"}, true))
+
+		e, err = s.Emoji(planetA, 1)
+		mustNil(t, err)
+
+		if e.Name != "third" {
+			t.Errorf("EmojiAdd with update did not replace emoji: got %q", e.Name)
+		}
+
+		mustNil(t, s.EmojiRemove(planetA, 1))
+
+		_, err = s.Emoji(planetA, 1)
+		requireNotFound(t, err)
+
+		emojis, err := s.Emojis(planetA)
+		mustNil(t, err)
+		requireIDs(t, emojis, func(e valour.Emoji) valour.EmojiID { return e.ID }, 2)
+
+		// Removing from an unknown planet is a no-op
+		mustNil(t, s.EmojiRemove(planetB, 1))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.EmojiSet(planetA, []valour.Emoji{{ID: 1}}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Emojis(planetA)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			_ = s.EmojiSet(planetA, []valour.Emoji{{ID: 1}, {ID: 2}}, true)
+			_, _ = s.Emoji(planetA, 1)
+			_, _ = s.Emojis(planetA)
+		})
+
+		emojis, err := s.Emojis(planetA)
+		mustNil(t, err)
+
+		if len(emojis) != 2 {
+			t.Errorf("expected 2 emojis, got %d", len(emojis))
+		}
+	})
+}
+
+// RunUserStore runs the suite against a UserStore
+func RunUserStore(t *testing.T, newStore func() store.UserStore) {
+	t.Run("Empty", func(t *testing.T) {
+		_, err := newStore().User(1)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.UserSet(&valour.User{ID: 1, Name: "first"}, false))
+		mustNil(t, s.UserSet(&valour.User{ID: 1, Name: "second"}, false))
+
+		u, err := s.User(1)
+		mustNil(t, err)
+
+		if u.Name != "first" {
+			t.Errorf("UserSet without update replaced existing user: got %q", u.Name)
+		}
+
+		mustNil(t, s.UserSet(&valour.User{ID: 1, Name: "third"}, true))
+
+		u, err = s.User(1)
+		mustNil(t, err)
+
+		if u.Name != "third" {
+			t.Errorf("UserSet with update did not replace user: got %q", u.Name)
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		s := newStore()
+
+		in := &valour.User{ID: 1, Name: "user"}
+		mustNil(t, s.UserSet(in, false))
+
+		in.Name = "changed"
+
+		u, err := s.User(1)
+		mustNil(t, err)
+
+		u.Name = "changed"
+
+		u, err = s.User(1)
+		mustNil(t, err)
+
+		if u.Name != "user" {
+			t.Errorf("store shares memory with callers: got %q", u.Name)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.UserSet(&valour.User{ID: 1}, false))
+		mustNil(t, s.UserRemove(1))
+
+		_, err := s.User(1)
+		requireNotFound(t, err)
+
+		// Removing an unknown user is not an error
+		mustNil(t, s.UserRemove(1))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.UserSet(&valour.User{ID: 1}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.User(1)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			u := &valour.User{ID: valour.UserID(i + 1)}
+
+			_ = s.UserSet(u, false)
+			_ = s.UserSet(u, true)
+			_, _ = s.User(u.ID)
+		})
+
+		for i := range concurrency {
+			if _, err := s.User(valour.UserID(i + 1)); err != nil {
+				t.Errorf("User(%d) after concurrent writes: %v", i+1, err)
+			}
+		}
+	})
+}
+
+// RunMessageStore runs the suite against a MessageStore
+func RunMessageStore(t *testing.T, newStore func() store.MessageStore) {
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore()
+
+		_, err := s.Message(1)
+		requireNotFound(t, err)
+
+		_, err = s.Messages(1)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MessageSet(&valour.Message{ID: 1, ChannelID: 1, Content: "first"}, false))
+		mustNil(t, s.MessageSet(&valour.Message{ID: 1, ChannelID: 1, Content: "second"}, false))
+
+		m, err := s.Message(1)
+		mustNil(t, err)
+
+		if m.Content != "first" {
+			t.Errorf("MessageSet without update replaced existing message: got %q", m.Content)
+		}
+
+		mustNil(t, s.MessageSet(&valour.Message{ID: 1, ChannelID: 1, Content: "third"}, true))
+
+		messages, err := s.Messages(1)
+		mustNil(t, err)
+
+		if len(messages) != 1 || messages[0].Content != "third" {
+			t.Errorf("MessageSet with update did not replace message in channel index: %+v", messages)
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		s := newStore()
+
+		// Inserted out of order, Messages must return them oldest first
+		mustNil(t, s.MessageSet(&valour.Message{ID: 3, ChannelID: 1}, false))
+		mustNil(t, s.MessageSet(&valour.Message{ID: 1, ChannelID: 1}, false))
+		mustNil(t, s.MessageSet(&valour.Message{ID: 2, ChannelID: 1}, false))
+		mustNil(t, s.MessageSet(&valour.Message{ID: 4, ChannelID: 2}, false))
+
+		messages, err := s.Messages(1)
+		mustNil(t, err)
+
+		var ids []valour.MessageID
+
+		for _, m := range messages {
+			ids = append(ids, m.ID)
+		}
+
+		if !slices.Equal(ids, []valour.MessageID{1, 2, 3}) {
+			t.Errorf("expected messages [1 2 3] in order, got %v", ids)
+		}
+
+		messages, err = s.Messages(2)
+		mustNil(t, err)
+		requireIDs(t, messages, func(m valour.Message) valour.MessageID { return m.ID }, 4)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MessageSet(&valour.Message{ID: 1, ChannelID: 1}, false))
+		mustNil(t, s.MessageSet(&valour.Message{ID: 2, ChannelID: 1}, false))
+		mustNil(t, s.MessageRemove(1))
+
+		_, err := s.Message(1)
+		requireNotFound(t, err)
+
+		messages, err := s.Messages(1)
+		mustNil(t, err)
+		requireIDs(t, messages, func(m valour.Message) valour.MessageID { return m.ID }, 2)
+
+		// Removing an unknown message is not an error
+		mustNil(t, s.MessageRemove(1))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		s := newStore()
+
+		mustNil(t, s.MessageSet(&valour.Message{ID: 1, ChannelID: 1}, false))
+		mustNil(t, s.Reset())
+
+		_, err := s.Message(1)
+		requireNotFound(t, err)
+
+		_, err = s.Messages(1)
+		requireNotFound(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore()
+
+		parallel(func(i int) {
+			m := &valour.Message{ID: valour.MessageID(i + 1), ChannelID: 1}
+
+			_ = s.MessageSet(m, false)
+			_ = s.MessageSet(m, true)
+			_, _ = s.Message(m.ID)
+			_, _ = s.Messages(1)
+		})
+
+		messages, err := s.Messages(1)
+		mustNil(t, err)
+
+		if len(messages) != concurrency {
+			t.Errorf("expected %d messages, got %d", concurrency, len(messages))
+		}
+	})
+}
+
+func testCabinetReset(t *testing.T, c *store.Cabinet) {
+	mustNil(t, c.MyselfSet(valour.User{ID: 1}, false))
+	mustNil(t, c.PlanetSet(&valour.Planet{ID: planetA}, false))
+	mustNil(t, c.ChannelSet(&valour.Channel{ID: 1, PlanetID: planetA}, false))
+	mustNil(t, c.MemberSet(&valour.Member{ID: 1, UserID: 1, PlanetID: planetA}, false))
+	mustNil(t, c.RoleSet(&valour.Role{ID: 1, PlanetID: planetA}, false))
+	mustNil(t, c.EmojiSet(planetA, []valour.Emoji{{ID: 1}}, false))
+	mustNil(t, c.UserSet(&valour.User{ID: 1}, false))
+	mustNil(t, c.PermissionsNodeSet(&valour.PermissionsNode{ID: 1, PlanetID: planetA}, false))
+	mustNil(t, c.MessageSet(&valour.Message{ID: 1, PlanetID: planetA, ChannelID: 1}, false))
+
+	mustNil(t, c.Reset())
+
+	_, err := c.Me()
+	requireNotFound(t, err)
+
+	_, err = c.Planet(planetA)
+	requireNotFound(t, err)
+
+	_, err = c.Channel(1)
+	requireNotFound(t, err)
+
+	_, err = c.Member(1)
+	requireNotFound(t, err)
+
+	_, err = c.Role(planetA, 1)
+	requireNotFound(t, err)
+
+	_, err = c.Emoji(planetA, 1)
+	requireNotFound(t, err)
+
+	_, err = c.User(1)
+	requireNotFound(t, err)
+
+	_, err = c.Message(1)
+	requireNotFound(t, err)
+
+	_, err = c.PermissionsNode(planetA, 1)
+	requireNotFound(t, err)
+}
+
+// parallel runs fn on concurrency goroutines and waits for all of them
+func parallel(fn func(i int)) {
+	var wg sync.WaitGroup
+
+	for i := range concurrency {
+		wg.Go(func() {
+			fn(i)
+		})
+	}
+
+	wg.Wait()
+}
+
+func mustNil(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func requireNotFound(t *testing.T, err error) {
+	t.Helper()
+
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected store.ErrNotFound, got %v", err)
+	}
+}
+
+// requireIDs checks that items contains exactly the expected IDs, in any order
+func requireIDs[T any, ID cmp.Ordered](t *testing.T, items []T, id func(T) ID, expected ...ID) {
+	t.Helper()
+
+	got := make([]ID, 0, len(items))
+
+	for _, item := range items {
+		got = append(got, id(item))
+	}
+
+	slices.Sort(got)
+	slices.Sort(expected)
+
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected IDs %v, got %v", expected, got)
+	}
+}
@@ -1,6 +1,87 @@
 package valour
 
-import "net/http"
+import (
+	"io"
+	"net/http"
+)
+
+type Users interface {
+	Me() (*User, error)
+	User(userID UserID) (*User, error)
+	SetPresence(state UserState) error
+	SetStatus(status string) error
+	Profile(userID UserID) (*UserProfile, error)
+	EditProfile(data EditProfileData) (*UserProfile, error)
+	UploadAvatar(fileName string, r io.Reader, size int64) error
+	UploadProfileBackground(fileName string, r io.Reader, size int64) (string, error)
+}
+
+// UserState is the presence a user has chosen
+type UserState int
+
+const (
+	// UserStateAutomatic shows the user as online or away based on their activity
+	UserStateAutomatic UserState = iota
+	UserStateOnline
+	UserStateAway
+	UserStateDoNotDisturb
+	UserStateOffline
+)
+
+func (s UserState) String() string {
+	switch s {
+	case UserStateAutomatic:
+		return "Automatic"
+	case UserStateOnline:
+		return "Online"
+	case UserStateAway:
+		return "Away"
+	case UserStateDoNotDisturb:
+		return "Do Not Disturb"
+	case UserStateOffline:
+		return "Offline"
+	default:
+		return "Unknown"
+	}
+}
+
+// UserProfile is the customizable profile shown when viewing a user
+type UserProfile struct {
+	ID              UserID  `json:"id"`
+	Headline        string  `json:"headline"`
+	Bio             string  `json:"bio"`
+	BorderColor     Color   `json:"borderColor"`
+	GlowColor       Color   `json:"glowColor"`
+	PrimaryColor    Color   `json:"primaryColor"`
+	SecondaryColor  Color   `json:"secondaryColor"`
+	TertiaryColor   Color   `json:"tertiaryColor"`
+	TextColor       Color   `json:"textColor"`
+	AnimatedBorder  bool    `json:"animatedBorder"`
+	BackgroundImage *string `json:"backgroundImage"`
+}
+
+// EditProfileData is a partial update to our profile, only fields that are set are sent and changed
+type EditProfileData struct {
+	Headline       *string `json:"headline,omitempty"`
+	Bio            *string `json:"bio,omitempty"`
+	BorderColor    *Color  `json:"borderColor,omitempty"`
+	GlowColor      *Color  `json:"glowColor,omitempty"`
+	PrimaryColor   *Color  `json:"primaryColor,omitempty"`
+	SecondaryColor *Color  `json:"secondaryColor,omitempty"`
+	TertiaryColor  *Color  `json:"tertiaryColor,omitempty"`
+	TextColor      *Color  `json:"textColor,omitempty"`
+	AnimatedBorder *bool   `json:"animatedBorder,omitempty"`
+
+	// BackgroundImage is the location of an image from UploadProfileBackground
+	BackgroundImage *string `json:"backgroundImage,omitempty"`
+}
+
+// editUserData is a partial update to our user, only fields that are set are sent and changed
+type editUserData struct {
+	ID            UserID     `json:"id"`
+	UserStateCode *UserState `json:"userStateCode,omitempty"`
+	Status        *string    `json:"status,omitempty"`
+}
 
 func (n *Node) Me() (*User, error) {
 	if n.me != nil {
@@ -25,3 +106,105 @@ func (n *Node) User(userID UserID) (*User, error) {
 
 	return &user, nil
 }
+
+// SetPresence sets our presence, such as online or do not disturb
+func (n *Node) SetPresence(state UserState) error {
+	return n.updateMe(editUserData{UserStateCode: &state})
+}
+
+// SetStatus sets our custom status message, an empty status clears it
+func (n *Node) SetStatus(status string) error {
+	return n.updateMe(editUserData{Status: &status})
+}
+
+// updateMe sends a partial update of our user to the API, leaving other fields unchanged
+// This request always goes to the primary node
+func (n *Node) updateMe(data editUserData) error {
+	if !n.IsPrimary() {
+		return n.Primary.updateMe(data)
+	}
+
+	me, err := n.Me()
+
+	if err != nil {
+		return err
+	}
+
+	data.ID = me.ID
+
+	return n.requestNoContent(http.MethodPut, me.ID.Route(), data)
+}
+
+// Profile retrieves a user's profile
+// This request always goes to the primary node
+func (n *Node) Profile(userID UserID) (*UserProfile, error) {
+	if !n.IsPrimary() {
+		return n.Primary.Profile(userID)
+	}
+
+	var profile UserProfile
+
+	if err := n.requestJSON(http.MethodGet, apiProfileBase+"/"+userID.String(), nil, &profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// EditProfile updates our profile's text, colors or background, only sending the fields that are set
+func (n *Node) EditProfile(data EditProfileData) (*UserProfile, error) {
+	if !n.IsPrimary() {
+		return n.Primary.EditProfile(data)
+	}
+
+	me, err := n.Me()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := struct {
+		EditProfileData
+		ID UserID `json:"id"`
+	}{
+		EditProfileData: data,
+		ID:              me.ID,
+	}
+
+	var updated UserProfile
+
+	if err := n.requestJSON(http.MethodPut, apiProfileBase+"/"+me.ID.String(), fields, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// UploadAvatar sets our avatar to a png, jpeg or gif image
+// This request always goes to the primary node
+func (n *Node) UploadAvatar(fileName string, r io.Reader, size int64) error {
+	if !n.IsPrimary() {
+		return n.Primary.UploadAvatar(fileName, r, size)
+	}
+
+	_, err := n.uploadImage("upload/avatar", fileName, r, size)
+
+	return err
+}
+
+// UploadProfileBackground uploads a profile background image, returning its location
+// to be set with EditProfileData.BackgroundImage.
+// This request always goes to the primary node
+func (n *Node) UploadProfileBackground(fileName string, r io.Reader, size int64) (string, error) {
+	if !n.IsPrimary() {
+		return n.Primary.UploadProfileBackground(fileName, r, size)
+	}
+
+	upload, err := n.uploadImage("upload/profilebg", fileName, r, size)
+
+	if err != nil {
+		return "", err
+	}
+
+	return upload.Location, nil
+}
@@ -0,0 +1,96 @@
+package valour
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// captureBody records the JSON fields of a request body, responding with resp
+func captureBody(t *testing.T, fields *map[string]json.RawMessage, resp any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		if err := json.Unmarshal(b, fields); err != nil {
+			t.Errorf("decoding body %s: %v", b, err)
+		}
+
+		writeJSON(w, resp)
+	}
+}
+
+func newUserTestNode(t *testing.T, mux *http.ServeMux) *Node {
+	mux.HandleFunc("GET /api/users/me", func(w http.ResponseWriter, r *http.Request) {
+		status := "busy"
+		writeJSON(w, User{ID: 5, Name: "me", Status: &status, UserStateCode: UserStateAway})
+	})
+
+	return newTestNode(t, mux)
+}
+
+func TestUpdateMeSendsChangedFields(t *testing.T) {
+	var fields map[string]json.RawMessage
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/users/5", captureBody(t, &fields, nil))
+
+	node := newUserTestNode(t, mux)
+
+	if err := node.SetPresence(UserStateDoNotDisturb); err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := slices.Sorted(maps.Keys(fields)); !slices.Equal(keys, []string{"id", "userStateCode"}) {
+		t.Fatalf("SetPresence sent %v, want only id and userStateCode", keys)
+	}
+
+	if string(fields["userStateCode"]) != "3" {
+		t.Fatalf("userStateCode = %s, want 3", fields["userStateCode"])
+	}
+
+	fields = nil
+
+	if err := node.SetStatus(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := slices.Sorted(maps.Keys(fields)); !slices.Equal(keys, []string{"id", "status"}) {
+		t.Fatalf("SetStatus sent %v, want only id and status", keys)
+	}
+}
+
+func TestEditProfileSendsChangedFields(t *testing.T) {
+	var fields map[string]json.RawMessage
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/userProfiles/5", captureBody(t, &fields, UserProfile{ID: 5, Headline: "hello"}))
+	mux.HandleFunc("GET /api/userProfiles/5", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("EditProfile should not need the current profile")
+	})
+
+	node := newUserTestNode(t, mux)
+
+	headline := "hello"
+	color := ColorFromInt(0x112233)
+
+	profile, err := node.EditProfile(EditProfileData{Headline: &headline, GlowColor: &color})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.Headline != "hello" {
+		t.Fatalf("headline = %q, want hello", profile.Headline)
+	}
+
+	if keys := slices.Sorted(maps.Keys(fields)); !slices.Equal(keys, []string{"glowColor", "headline", "id"}) {
+		t.Fatalf("EditProfile sent %v, want only id, headline and glowColor", keys)
+	}
+
+	if string(fields["glowColor"]) != `"#112233"` {
+		t.Fatalf("glowColor = %s, want \"#112233\"", fields["glowColor"])
+	}
+}
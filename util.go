@@ -3,3 +3,10 @@ package valour
 func Ref[V any](v V) *V {
 	return &v
 }
+
+// setIfNotNil sets dst to the value of src, if src is set
+func setIfNotNil[V any](dst *V, src *V) {
+	if src != nil {
+		*dst = *src
+	}
+}
@@ -5,12 +5,21 @@ import (
 )
 
 const (
-	baseClientAddress    = "https://api.valour.gg"
-	apiBase              = "api"
-	apiPlanetBase        = apiBase + "/planets"
-	apiMessageBase       = apiBase + "/messages"
-	apiUserBase          = apiBase + "/users"
-	apiPlanetInitialData = "initialData"
+	baseClientAddress = "https://api.valour.gg"
+	apiBase           = "api"
+	apiPlanetBase     = apiBase + "/planets"
+	apiMessageBase    = apiBase + "/messages"
+	apiUserBase       = apiBase + "/users"
+	apiBanBase        = apiBase + "/bans"
+	apiMemberBase     = apiBase + "/members"
+	apiInviteBase     = apiBase + "/invites"
+	apiChannelBase    = apiBase + "/channels"
+	apiFriendBase     = apiBase + "/userfriends"
+	apiProfileBase    = apiBase + "/userProfiles"
+	apiEmojiBase      = apiBase + "/emojis"
+
+	apiPermissionsNodeBase = apiBase + "/permissionsnodes"
+	apiPlanetInitialData   = "initialData"
 )
 
 type BaseClient struct {